@@ -9,6 +9,47 @@ type Suggestion struct {
 	Type        string
 	Scope       string
 	Description string
+	Issue       string // Issue reference for the trailer, e.g. "JIRA-123"
+}
+
+// IssueTrailer is the trailer key used for Suggestion.Issue.
+const IssueTrailer = "Refs"
+
+// Header returns the suggested subject line: type(scope): description
+func (s Suggestion) Header() string {
+	return Header{Type: s.Type, Scope: s.Scope, Description: s.Description}.String()
+}
+
+// Trailers returns the suggested trailer lines, e.g. "Refs: JIRA-123".
+func (s Suggestion) Trailers() []string {
+	if s.Issue == "" {
+		return nil
+	}
+	return []string{IssueTrailer + ": " + s.Issue}
+}
+
+// Message returns the full suggested commit message including trailers.
+func (s Suggestion) Message() string {
+	msg := s.Header()
+	if trailers := s.Trailers(); len(trailers) > 0 {
+		msg += "\n\n" + strings.Join(trailers, "\n")
+	}
+	return msg
+}
+
+// AnalyzeDiffOnBranch is AnalyzeDiff seeded with the context of the branch
+// being committed to. The branch's type and scope take precedence over the
+// file heuristics, and its issue becomes a trailer.
+func AnalyzeDiffOnBranch(diff string, branch BranchContext) Suggestion {
+	suggestion := AnalyzeDiff(diff)
+	if branch.Type != "" {
+		suggestion.Type = branch.Type
+	}
+	if branch.Scope != "" {
+		suggestion.Scope = branch.Scope
+	}
+	suggestion.Issue = branch.Issue
+	return suggestion
 }
 
 // AnalyzeDiff analyzes the staged diff and allows to categorize changes.
@@ -49,3 +49,20 @@ index 8f23..123 100644
 		})
 	}
 }
+
+func TestAnalyzeDiffOnBranch(t *testing.T) {
+	diff := `diff --git a/internal/ui/status.go b/internal/ui/status.go`
+
+	got := AnalyzeDiffOnBranch(diff, BranchContext{Type: "fix", Scope: "status", Issue: "JIRA-123"})
+	if got.Type != "fix" || got.Scope != "status" || got.Description != "implement feature" {
+		t.Errorf("expected branch type and scope to seed the suggestion, got %+v", got)
+	}
+	if want := "fix(status): implement feature\n\nRefs: JIRA-123"; got.Message() != want {
+		t.Errorf("Message() = %q, want %q", got.Message(), want)
+	}
+
+	got = AnalyzeDiffOnBranch(diff, BranchContext{})
+	if got.Message() != "feat: implement feature" {
+		t.Errorf("expected no trailer without a branch context, got %q", got.Message())
+	}
+}
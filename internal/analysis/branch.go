@@ -0,0 +1,177 @@
+package analysis
+
+import (
+	"regexp"
+	"strings"
+)
+
+// DefaultBranchTemplate names new branches like "feat/ui-diff-pane".
+const DefaultBranchTemplate = "<type>/<scope>-<slug>"
+
+var (
+	slugRe    = regexp.MustCompile(`[^a-z0-9]+`)
+	dashesRe  = regexp.MustCompile(`-{2,}`)
+	slashesRe = regexp.MustCompile(`/{2,}`)
+)
+
+// maxSlug limits the description part of generated branch names.
+const maxSlug = 40
+
+// Slugify lowercases s and joins its words with dashes, e.g. "Diff Pane!" -> "diff-pane".
+func Slugify(s string) string {
+	slug := strings.Trim(slugRe.ReplaceAllString(strings.ToLower(s), "-"), "-")
+	if len(slug) > maxSlug {
+		slug = strings.TrimRight(slug[:maxSlug], "-")
+	}
+	return slug
+}
+
+// BranchName fills a branch template from a header. The placeholders <type>,
+// <scope> and <slug> (the slugified description) are replaced, and separators
+// left dangling by empty placeholders are removed.
+func BranchName(template string, h Header) string {
+	name := strings.NewReplacer(
+		"<type>", Slugify(h.Type),
+		"<scope>", Slugify(h.Scope),
+		"<slug>", Slugify(h.Description),
+	).Replace(template)
+
+	name = dashesRe.ReplaceAllString(name, "-")
+	name = slashesRe.ReplaceAllString(name, "/")
+	name = strings.ReplaceAll(name, "/-", "/")
+	name = strings.ReplaceAll(name, "-/", "/")
+	return strings.Trim(name, "-/")
+}
+
+// Types are the Conventional Commit types recognized in branch names.
+var Types = []string{"feat", "fix", "docs", "style", "refactor", "perf", "test", "build", "ci", "chore", "revert"}
+
+// typeAliases maps common branch prefixes to Conventional types.
+var typeAliases = map[string]string{
+	"feature": "feat",
+	"bugfix":  "fix",
+	"hotfix":  "fix",
+	"doc":     "docs",
+}
+
+// issuePattern matches a ticket key like "JIRA-123" or a plain issue number.
+const issuePattern = `(?P<issue>[A-Za-z][A-Za-z0-9]*-\d+|\d+)(?:-|$)`
+
+// DefaultBranchPatterns parse names like "feat/ui-diff-pane", "feat/ui/diff-pane",
+// "fix/JIRA-123-status-crash", "fix/42-crash" and "users/me/PROJ-7-thing".
+// Patterns use the named groups type, scope and issue; all are optional.
+var DefaultBranchPatterns = []string{
+	`^(?P<type>[a-z]+)/(?:` + issuePattern + `)?(?:(?P<scope>[a-z0-9]+)[-/])?`,
+	`(?:^|/)(?P<issue>[A-Z][A-Z0-9]+-\d+)(?:-|$)`,
+}
+
+// slugVerbs start descriptions rather than name a scope, as in
+// "feat/add-login-page": a scope matching one of them is dropped.
+var slugVerbs = map[string]bool{
+	"add": true, "allow": true, "bump": true, "change": true, "clean": true,
+	"create": true, "delete": true, "disable": true, "drop": true, "enable": true,
+	"fix": true, "handle": true, "improve": true, "make": true, "move": true,
+	"remove": true, "rename": true, "support": true, "update": true, "use": true,
+}
+
+// TemplatePattern turns a branch template into a pattern that parses the
+// names it generates. An issue at the start of <slug> is captured too.
+func TemplatePattern(template string) string {
+	var pattern strings.Builder
+	pattern.WriteString("^")
+	rest := template
+	for rest != "" {
+		start := strings.Index(rest, "<")
+		end := strings.Index(rest, ">")
+		if start < 0 || end < start {
+			pattern.WriteString(regexp.QuoteMeta(rest))
+			break
+		}
+		pattern.WriteString(regexp.QuoteMeta(rest[:start]))
+		switch rest[start+1 : end] {
+		case "type":
+			pattern.WriteString(`(?P<type>[a-z0-9]+)`)
+		case "scope":
+			pattern.WriteString(`(?P<scope>[a-z0-9]+)`)
+		case "slug":
+			pattern.WriteString(`(?:` + issuePattern + `)?.*`)
+		default:
+			pattern.WriteString(regexp.QuoteMeta(rest[start : end+1]))
+		}
+		rest = rest[end+1:]
+	}
+	pattern.WriteString("$")
+	return pattern.String()
+}
+
+// BranchContext is what a branch name says about the work on it.
+type BranchContext struct {
+	Type  string
+	Scope string
+	Issue string // "JIRA-123", or "#42" for plain numbers
+}
+
+// CompileBranchPatterns compiles branch patterns, failing on the first invalid one.
+func CompileBranchPatterns(patterns []string) ([]*regexp.Regexp, error) {
+	compiled := make([]*regexp.Regexp, 0, len(patterns))
+	for _, p := range patterns {
+		re, err := regexp.Compile(p)
+		if err != nil {
+			return nil, err
+		}
+		compiled = append(compiled, re)
+	}
+	return compiled, nil
+}
+
+// ParseBranch extracts type, scope and issue from a branch name using the
+// first pattern that matches. Matches whose type is not a known Conventional
+// type (after aliases) are skipped, so "users/me/x" yields no type.
+func ParseBranch(branch string, patterns []*regexp.Regexp) (BranchContext, bool) {
+	for _, re := range patterns {
+		m := re.FindStringSubmatch(branch)
+		if m == nil {
+			continue
+		}
+		var ctx BranchContext
+		for i, name := range re.SubexpNames() {
+			switch name {
+			case "type":
+				ctx.Type = strings.ToLower(m[i])
+			case "scope":
+				if !slugVerbs[m[i]] {
+					ctx.Scope = m[i]
+				}
+			case "issue":
+				ctx.Issue = m[i]
+			}
+		}
+
+		if alias, ok := typeAliases[ctx.Type]; ok {
+			ctx.Type = alias
+		}
+		if ctx.Type != "" && !isType(ctx.Type) {
+			continue
+		}
+		if ctx.Issue != "" {
+			if strings.Trim(ctx.Issue, "0123456789") == "" {
+				ctx.Issue = "#" + ctx.Issue
+			} else {
+				ctx.Issue = strings.ToUpper(ctx.Issue)
+			}
+		}
+		if ctx != (BranchContext{}) {
+			return ctx, true
+		}
+	}
+	return BranchContext{}, false
+}
+
+func isType(t string) bool {
+	for _, known := range Types {
+		if t == known {
+			return true
+		}
+	}
+	return false
+}
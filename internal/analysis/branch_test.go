@@ -0,0 +1,92 @@
+package analysis
+
+import "testing"
+
+func TestBranchName(t *testing.T) {
+	tests := []struct {
+		template string
+		header   Header
+		want     string
+	}{
+		{DefaultBranchTemplate, Header{Type: "feat", Scope: "ui", Description: "Diff pane"}, "feat/ui-diff-pane"},
+		{DefaultBranchTemplate, Header{Type: "fix", Description: "crash on empty repo!"}, "fix/crash-on-empty-repo"},
+		{"<type>/<slug>", Header{Type: "docs", Scope: "x", Description: "  README  "}, "docs/readme"},
+		{"users/me/<scope>/<slug>", Header{Type: "feat", Description: "add log"}, "users/me/add-log"},
+	}
+	for _, tt := range tests {
+		if got := BranchName(tt.template, tt.header); got != tt.want {
+			t.Errorf("BranchName(%q, %+v) = %q, want %q", tt.template, tt.header, got, tt.want)
+		}
+	}
+}
+
+func TestSlugifyLimitsLength(t *testing.T) {
+	slug := Slugify("a very long description that keeps going well past any sensible branch name")
+	if len(slug) > maxSlug || slug[len(slug)-1] == '-' {
+		t.Errorf("unexpected slug %q", slug)
+	}
+}
+
+func TestParseBranch(t *testing.T) {
+	patterns, err := CompileBranchPatterns(DefaultBranchPatterns)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		branch string
+		want   BranchContext
+		ok     bool
+	}{
+		{"feat/ui-diff-pane", BranchContext{Type: "feat", Scope: "ui"}, true},
+		{"fix/JIRA-123-status-crash", BranchContext{Type: "fix", Scope: "status", Issue: "JIRA-123"}, true},
+		{"feat/ui/diff-pane", BranchContext{Type: "feat", Scope: "ui"}, true},
+		{"feat/add-login-page", BranchContext{Type: "feat"}, true},
+		{"feat/diff-pane", BranchContext{Type: "feat", Scope: "diff"}, true},
+		{"fix/jira-123", BranchContext{Type: "fix", Issue: "JIRA-123"}, true},
+		{"bugfix/42-crash", BranchContext{Type: "fix", Issue: "#42"}, true},
+		{"docs/readme", BranchContext{Type: "docs"}, true},
+		{"users/me/PROJ-7-thing", BranchContext{Issue: "PROJ-7"}, true},
+		{"users/me/thing", BranchContext{}, false},
+		{"main", BranchContext{}, false},
+	}
+	for _, tt := range tests {
+		got, ok := ParseBranch(tt.branch, patterns)
+		if ok != tt.ok || got != tt.want {
+			t.Errorf("ParseBranch(%q) = %+v, %v; want %+v, %v", tt.branch, got, ok, tt.want, tt.ok)
+		}
+	}
+}
+
+func TestParseBranchCustomPattern(t *testing.T) {
+	patterns, err := CompileBranchPatterns([]string{`^(?P<scope>[a-z]+)/(?P<issue>\d+)$`})
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, ok := ParseBranch("billing/881", patterns)
+	if !ok || got != (BranchContext{Scope: "billing", Issue: "#881"}) {
+		t.Errorf("unexpected context %+v, %v", got, ok)
+	}
+}
+
+func TestParseBranchTemplatePattern(t *testing.T) {
+	patterns, err := CompileBranchPatterns([]string{TemplatePattern(DefaultBranchTemplate)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		branch string
+		want   BranchContext
+		ok     bool
+	}{
+		{"feat/ui-diff-pane", BranchContext{Type: "feat", Scope: "ui"}, true},
+		{"fix/api-42-crash", BranchContext{Type: "fix", Scope: "api", Issue: "#42"}, true},
+		{"feat/ui", BranchContext{}, false},
+		{"users/me/thing", BranchContext{}, false},
+	}
+	for _, tt := range tests {
+		got, ok := ParseBranch(tt.branch, patterns)
+		if ok != tt.ok || got != tt.want {
+			t.Errorf("ParseBranch(%q) = %+v, %v; want %+v, %v", tt.branch, got, ok, tt.want, tt.ok)
+		}
+	}
+}
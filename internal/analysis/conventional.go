@@ -0,0 +1,50 @@
+package analysis
+
+import (
+	"regexp"
+	"strings"
+)
+
+// Header is a parsed Conventional Commit header line: type(scope)!: description
+type Header struct {
+	Type        string
+	Scope       string
+	Breaking    bool
+	Description string
+}
+
+var headerRe = regexp.MustCompile(`^(\w+)(?:\(([^)]*)\))?(!)?: (.+)$`)
+
+// FirstLine returns the first line of s, e.g. the subject of a commit message.
+func FirstLine(s string) string {
+	line, _, _ := strings.Cut(s, "\n")
+	return line
+}
+
+// ParseHeader parses the first line of a commit message.
+// The second return value is false if the line is not a Conventional Commit header.
+func ParseHeader(subject string) (Header, bool) {
+	line := strings.TrimSpace(strings.SplitN(subject, "\n", 2)[0])
+	m := headerRe.FindStringSubmatch(line)
+	if m == nil {
+		return Header{Description: line}, false
+	}
+	return Header{
+		Type:        strings.ToLower(m[1]),
+		Scope:       m[2],
+		Breaking:    m[3] == "!",
+		Description: m[4],
+	}, true
+}
+
+// String formats the header back into a commit subject.
+func (h Header) String() string {
+	s := h.Type
+	if h.Scope != "" {
+		s += "(" + h.Scope + ")"
+	}
+	if h.Breaking {
+		s += "!"
+	}
+	return s + ": " + h.Description
+}
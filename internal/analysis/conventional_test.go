@@ -0,0 +1,23 @@
+package analysis
+
+import "testing"
+
+func TestParseHeader(t *testing.T) {
+	tests := []struct {
+		subject string
+		want    Header
+		ok      bool
+	}{
+		{"feat(ui): add log view", Header{Type: "feat", Scope: "ui", Description: "add log view"}, true},
+		{"fix: crash on empty repo", Header{Type: "fix", Description: "crash on empty repo"}, true},
+		{"refactor(git)!: drop porcelain v1", Header{Type: "refactor", Scope: "git", Breaking: true, Description: "drop porcelain v1"}, true},
+		{"Merge branch 'main'", Header{Description: "Merge branch 'main'"}, false},
+	}
+
+	for _, tt := range tests {
+		got, ok := ParseHeader(tt.subject)
+		if ok != tt.ok || got != tt.want {
+			t.Errorf("ParseHeader(%q) = %+v, %v; want %+v, %v", tt.subject, got, ok, tt.want, tt.ok)
+		}
+	}
+}
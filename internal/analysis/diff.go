@@ -0,0 +1,107 @@
+package analysis
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// FileDiff is the change of one file in a unified diff.
+type FileDiff struct {
+	Path   string // Path after the change (before it, for a deletion)
+	Hunks  []Hunk
+	Binary bool
+}
+
+// Hunk is a block of changes with its line ranges before and after.
+type Hunk struct {
+	OldStart, OldLines int
+	NewStart, NewLines int
+	Added              []Line // Added lines, numbered in the new file
+}
+
+// Line is a numbered line of a file.
+type Line struct {
+	Number int
+	Text   string
+}
+
+var hunkRe = regexp.MustCompile(`^@@ -(\d+)(?:,(\d+))? \+(\d+)(?:,(\d+))? @@`)
+
+// ParseDiff splits a unified diff (as printed by git diff or git show) into files and hunks.
+func ParseDiff(diff string) []FileDiff {
+	var files []FileDiff
+	var file *FileDiff
+	var hunk *Hunk
+	newLine := 0
+
+	for _, line := range strings.Split(diff, "\n") {
+		switch {
+		case strings.HasPrefix(line, "diff --git "):
+			files = append(files, FileDiff{})
+			file = &files[len(files)-1]
+			hunk = nil
+			// Fallback for diffs without ---/+++ lines (binary, mode or rename only)
+			if i := strings.LastIndex(line, " b/"); i >= 0 {
+				file.Path = line[i+3:]
+			}
+		case file == nil:
+			continue
+		case hunk == nil && strings.HasPrefix(line, "--- "):
+			if p := diffPath(line[4:]); p != "" {
+				file.Path = p
+			}
+		case hunk == nil && strings.HasPrefix(line, "+++ "):
+			if p := diffPath(line[4:]); p != "" {
+				file.Path = p
+			}
+		case strings.HasPrefix(line, "Binary files "):
+			file.Binary = true
+		case strings.HasPrefix(line, "@@ "):
+			m := hunkRe.FindStringSubmatch(line)
+			if m == nil {
+				continue
+			}
+			file.Hunks = append(file.Hunks, Hunk{
+				OldStart: atoi(m[1]), OldLines: countOrOne(m[2]),
+				NewStart: atoi(m[3]), NewLines: countOrOne(m[4]),
+			})
+			hunk = &file.Hunks[len(file.Hunks)-1]
+			newLine = hunk.NewStart
+		case hunk != nil && strings.HasPrefix(line, "+"):
+			hunk.Added = append(hunk.Added, Line{Number: newLine, Text: line[1:]})
+			newLine++
+		case hunk != nil && strings.HasPrefix(line, " "):
+			newLine++
+		}
+	}
+	return files
+}
+
+// diffPath returns the path of a ---/+++ line, "" for /dev/null.
+func diffPath(p string) string {
+	if strings.HasPrefix(p, `"`) {
+		if unquoted, err := strconv.Unquote(p); err == nil {
+			p = unquoted
+		}
+	}
+	if p == "/dev/null" {
+		return ""
+	}
+	if len(p) > 2 && (p[:2] == "a/" || p[:2] == "b/") {
+		return p[2:]
+	}
+	return p
+}
+
+func atoi(s string) int {
+	n, _ := strconv.Atoi(s)
+	return n
+}
+
+func countOrOne(s string) int {
+	if s == "" {
+		return 1
+	}
+	return atoi(s)
+}
@@ -0,0 +1,75 @@
+package analysis
+
+import "testing"
+
+const sampleDiff = `diff --git a/cli/fix.go b/cli/fix.go
+index 1111111..2222222 100644
+--- a/cli/fix.go
++++ b/cli/fix.go
+@@ -10,3 +10,4 @@ func fix() {
+ 	a := 1
+-	b := 2
++	b := 3
++	c := 4
+ 	return
+@@ -40 +41,0 @@ func other() {
+-	gone()
+diff --git a/old.txt b/old.txt
+deleted file mode 100644
+--- a/old.txt
++++ /dev/null
+@@ -1 +0,0 @@
+-bye
+diff --git a/logo.png b/logo.png
+new file mode 100644
+Binary files /dev/null and b/logo.png differ
+`
+
+func TestParseDiff(t *testing.T) {
+	files := ParseDiff(sampleDiff)
+	if len(files) != 3 {
+		t.Fatalf("expected 3 files, got %d", len(files))
+	}
+
+	fix := files[0]
+	if fix.Path != "cli/fix.go" || len(fix.Hunks) != 2 {
+		t.Fatalf("unexpected first file %+v", fix)
+	}
+	h := fix.Hunks[0]
+	if h.OldStart != 10 || h.OldLines != 3 || h.NewStart != 10 || h.NewLines != 4 {
+		t.Errorf("unexpected hunk ranges %+v", h)
+	}
+	if len(h.Added) != 2 || h.Added[0] != (Line{11, "\tb := 3"}) || h.Added[1].Number != 12 {
+		t.Errorf("unexpected added lines %+v", h.Added)
+	}
+	if d := fix.Hunks[1]; d.OldStart != 40 || d.OldLines != 1 || d.NewLines != 0 {
+		t.Errorf("unexpected deletion hunk %+v", d)
+	}
+
+	if files[1].Path != "old.txt" {
+		t.Errorf("expected a deleted file to keep its old path, got %q", files[1].Path)
+	}
+	if files[2].Path != "logo.png" || !files[2].Binary {
+		t.Errorf("expected a binary logo.png, got %+v", files[2])
+	}
+}
+
+func TestRankFixups(t *testing.T) {
+	staged := []FileDiff{{Path: "a.go", Hunks: []Hunk{{OldStart: 20, OldLines: 2}}}}
+	commits := [][]FileDiff{
+		{{Path: "b.go", Hunks: []Hunk{{NewStart: 20, NewLines: 2}}}}, // Other file
+		{{Path: "a.go", Hunks: []Hunk{{NewStart: 1, NewLines: 5}}}},  // Same file, other lines
+		{{Path: "a.go", Hunks: []Hunk{{NewStart: 18, NewLines: 4}}}}, // Same lines
+	}
+	var scores []FixupScore
+	for _, c := range commits {
+		scores = append(scores, ScoreFixup(staged, c))
+	}
+	if scores[2] != (FixupScore{Files: 1, Hunks: 1}) {
+		t.Errorf("expected the overlapping commit to score 1 file and 1 hunk, got %+v", scores[2])
+	}
+	order := RankFixups(scores)
+	if order[0] != 2 || order[1] != 1 || order[2] != 0 {
+		t.Errorf("RankFixups() = %v, want [2 1 0]", order)
+	}
+}
@@ -0,0 +1,62 @@
+package analysis
+
+import "sort"
+
+// FixupScore is how much a commit overlaps with the staged changes.
+type FixupScore struct {
+	Files int // Files changed by both
+	Hunks int // Staged hunks touching lines the commit changed
+}
+
+// ScoreFixup compares the staged changes (whose old ranges are lines of HEAD)
+// with the changes of a commit (whose new ranges are lines as it left them).
+// Later commits to the same file shift lines, so hunk overlap is a hint.
+func ScoreFixup(staged, commit []FileDiff) FixupScore {
+	changed := make(map[string][]Hunk)
+	for _, f := range commit {
+		changed[f.Path] = f.Hunks
+	}
+
+	var score FixupScore
+	for _, f := range staged {
+		hunks, ok := changed[f.Path]
+		if !ok {
+			continue
+		}
+		score.Files++
+		for _, h := range f.Hunks {
+			for _, c := range hunks {
+				if overlaps(h.OldStart, h.OldLines, c.NewStart, c.NewLines) {
+					score.Hunks++
+					break
+				}
+			}
+		}
+	}
+	return score
+}
+
+// overlaps reports whether two line ranges intersect. An empty range (pure
+// insertion or deletion) is treated as the single line it sits at.
+func overlaps(start1, len1, start2, len2 int) bool {
+	end1 := start1 + max(len1, 1)
+	end2 := start2 + max(len2, 1)
+	return start1 < end2 && start2 < end1
+}
+
+// RankFixups orders commits (given newest first) by their score, best first.
+// Ties keep the newest commit first.
+func RankFixups(scores []FixupScore) []int {
+	order := make([]int, len(scores))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(a, b int) bool {
+		sa, sb := scores[order[a]], scores[order[b]]
+		if sa.Hunks != sb.Hunks {
+			return sa.Hunks > sb.Hunks
+		}
+		return sa.Files > sb.Files
+	})
+	return order
+}
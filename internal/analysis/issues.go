@@ -0,0 +1,58 @@
+package analysis
+
+import (
+	"regexp"
+	"strings"
+)
+
+// DefaultIssuePattern matches tracker keys like "ABC-42" and GitHub-style "#123".
+const DefaultIssuePattern = `\b[A-Z][A-Z0-9]+-\d+\b|#\d+\b`
+
+// DefaultTrailerKeys are the trailers an issue can be referenced with.
+var DefaultTrailerKeys = []string{IssueTrailer, "Closes"}
+
+var trailerRe = regexp.MustCompile(`^([A-Za-z][A-Za-z0-9-]*): (.+)$`)
+
+// ExtractIssues returns the unique issue keys found in text, in order of appearance.
+func ExtractIssues(text string, pattern *regexp.Regexp) []string {
+	seen := make(map[string]bool)
+	var issues []string
+	for _, key := range pattern.FindAllString(text, -1) {
+		if !seen[key] {
+			seen[key] = true
+			issues = append(issues, key)
+		}
+	}
+	return issues
+}
+
+// Trailers returns the "Key: value" lines of the last paragraph of a message.
+// A message consisting of a single paragraph has no trailers.
+func Trailers(message string) [][2]string {
+	paragraphs := strings.Split(strings.TrimSpace(message), "\n\n")
+	if len(paragraphs) < 2 {
+		return nil
+	}
+	var trailers [][2]string
+	for _, line := range strings.Split(paragraphs[len(paragraphs)-1], "\n") {
+		m := trailerRe.FindStringSubmatch(strings.TrimSpace(line))
+		if m == nil {
+			return nil // Not a trailer block
+		}
+		trailers = append(trailers, [2]string{m[1], m[2]})
+	}
+	return trailers
+}
+
+// HasIssueTrailer reports whether a message references an issue with one of
+// the trailer keys (compared case-insensitively).
+func HasIssueTrailer(message string, keys []string) bool {
+	for _, t := range Trailers(message) {
+		for _, key := range keys {
+			if strings.EqualFold(t[0], key) {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,76 @@
+package analysis
+
+import (
+	"regexp"
+	"testing"
+)
+
+func TestExtractIssues(t *testing.T) {
+	re := regexp.MustCompile(DefaultIssuePattern)
+	got := ExtractIssues("fix: crash (ABC-42)\n\nRefs: #123\nSee ABC-42 and x#9", re)
+	want := []string{"ABC-42", "#123", "#9"}
+	if len(got) != len(want) {
+		t.Fatalf("ExtractIssues() = %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("issue %d = %q, want %q", i, got[i], want[i])
+		}
+	}
+}
+
+func TestLint(t *testing.T) {
+	rules := LintRules{RequireIssue: []string{"feat", "fix"}, TrailerKeys: DefaultTrailerKeys}
+	tests := []struct {
+		message  string
+		problems int
+	}{
+		{"fix(ui): crash\n\nCloses: ABC-42", 0},
+		{"fix(ui): crash\n\nrefs: #1\nSigned-off-by: A <a@b.c>", 0},
+		{"fix(ui): crash", 1},
+		{"fix(ui): crash\n\nMentions Refs: #1 inline", 1},
+		{"docs: readme", 0},
+		{"update stuff", 1},
+		{"wip: stuff", 1},
+		{"fixup! fix(ui): crash", 0},
+	}
+	for _, tt := range tests {
+		if got := Lint(tt.message, rules); len(got) != tt.problems {
+			t.Errorf("Lint(%q) = %v, want %d problems", tt.message, got, tt.problems)
+		}
+	}
+}
+
+func TestIsWorkInProgress(t *testing.T) {
+	tests := map[string]bool{
+		"wip":                    true,
+		"WIP: half done":         true,
+		"[wip] parser":           true,
+		"fixup! fix(ui): crash":  true,
+		"squash! feat: x":        true,
+		"feat(ui): wipe cache":   false,
+		"wipe: not a real type":  false,
+		"fix: handle WIP status": false,
+	}
+	for message, want := range tests {
+		if got := IsWorkInProgress(message); got != want {
+			t.Errorf("IsWorkInProgress(%q) = %v, want %v", message, got, want)
+		}
+	}
+}
+
+func TestIsAutosquash(t *testing.T) {
+	tests := map[string]bool{
+		"fixup! fix(ui): crash":      true,
+		"squash! feat: x":            true,
+		"amend! feat: x\n\nbody":     true,
+		"  fixup! indented":          true,
+		"wip":                        false,
+		"fix: handle fixup! commits": false,
+	}
+	for message, want := range tests {
+		if got := IsAutosquash(message); got != want {
+			t.Errorf("IsAutosquash(%q) = %v, want %v", message, got, want)
+		}
+	}
+}
@@ -0,0 +1,79 @@
+package analysis
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// LintRules configures Lint.
+type LintRules struct {
+	RequireIssue []string // Types that must reference an issue, e.g. "feat", "fix"
+	TrailerKeys  []string // Trailers that count as an issue reference
+}
+
+// Lint checks a commit message and returns its problems (none if it is fine).
+// fixup!/squash!/amend! commits are skipped: they are checked once squashed.
+func Lint(message string, rules LintRules) []string {
+	if IsAutosquash(message) {
+		return nil
+	}
+	subject := subjectOf(message)
+
+	h, ok := ParseHeader(subject)
+	if !ok {
+		return []string{"subject is not a Conventional Commit header (type(scope): description)"}
+	}
+
+	var problems []string
+	if !isType(h.Type) {
+		problems = append(problems, fmt.Sprintf("unknown type %q (use %s)", h.Type, strings.Join(Types, ", ")))
+	}
+	for _, t := range rules.RequireIssue {
+		if strings.EqualFold(t, h.Type) && !HasIssueTrailer(message, rules.TrailerKeys) {
+			problems = append(problems, fmt.Sprintf("%s commits must reference an issue (%s trailer)",
+				h.Type, strings.Join(rules.TrailerKeys, ": or ")+":"))
+			break
+		}
+	}
+	return problems
+}
+
+// autosquashPrefixes start the subjects of commits made to be folded into
+// another one by 'git rebase --autosquash'.
+var autosquashPrefixes = []string{"fixup! ", "squash! ", "amend! "}
+
+// IsAutosquash reports whether a commit is a fixup!/squash!/amend! commit.
+func IsAutosquash(message string) bool {
+	subject := subjectOf(message)
+	for _, prefix := range autosquashPrefixes {
+		if strings.HasPrefix(subject, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// trimAutosquash removes the fixup!/squash!/amend! prefixes of a subject.
+func trimAutosquash(subject string) string {
+	for IsAutosquash(subject) {
+		for _, prefix := range autosquashPrefixes {
+			subject = strings.TrimPrefix(subject, prefix)
+		}
+	}
+	return subject
+}
+
+// subjectOf returns the trimmed first line of a commit message.
+func subjectOf(message string) string {
+	return strings.TrimSpace(FirstLine(strings.TrimSpace(message)))
+}
+
+// wipRe matches subjects marking unfinished work: "wip", "WIP: x", "[wip] x".
+var wipRe = regexp.MustCompile(`(?i)^(\[wip\]|wip\b)`)
+
+// IsWorkInProgress reports whether a commit is not meant to be shared as is:
+// a WIP commit or a fixup!/squash!/amend! commit waiting to be squashed.
+func IsWorkInProgress(message string) bool {
+	return IsAutosquash(message) || wipRe.MatchString(subjectOf(message))
+}
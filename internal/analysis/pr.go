@@ -0,0 +1,324 @@
+package analysis
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// PRCommit is a commit going into a pull request.
+type PRCommit struct {
+	ShortHash string
+	Message   string
+}
+
+// DiffStat summarises the changes of a pull request.
+type DiffStat struct {
+	Files   int
+	Added   int
+	Deleted int
+}
+
+// PRChange is a commit listed in a pull request description.
+type PRChange struct {
+	Scope       string
+	Description string
+	ShortHash   string
+}
+
+// PRGroup holds the changes of one Conventional type. Type is "" for commits
+// without a Conventional header.
+type PRGroup struct {
+	Type    string
+	Title   string
+	Changes []PRChange
+}
+
+// PRIssue is an issue referenced by a pull request. Closes is set when a
+// commit closes it (Closes:, Fixes: or Resolves: trailer).
+type PRIssue struct {
+	Key    string
+	Closes bool
+}
+
+// PullRequest is the description generated for a range of commits.
+type PullRequest struct {
+	Title    string
+	Commits  int
+	Groups   []PRGroup
+	Breaking []string
+	Issues   []PRIssue
+	Stat     DiffStat
+}
+
+// typeTitles are the section titles of the Conventional types.
+var typeTitles = map[string]string{
+	"feat":     "Features",
+	"fix":      "Bug Fixes",
+	"docs":     "Documentation",
+	"style":    "Style",
+	"refactor": "Refactoring",
+	"perf":     "Performance",
+	"test":     "Tests",
+	"build":    "Build",
+	"ci":       "CI",
+	"chore":    "Chores",
+	"revert":   "Reverts",
+}
+
+// closingKeys are the trailers that close an issue when the PR is merged.
+var closingKeys = []string{"Closes", "Fixes", "Resolves"}
+
+var breakingRe = regexp.MustCompile(`^BREAKING[ -]CHANGE: (.+)$`)
+
+// DescribePR builds a pull request description from commits (oldest first).
+// fixup!/squash! and WIP commits are left out of the changes.
+func DescribePR(commits []PRCommit, stat DiffStat, issuePattern *regexp.Regexp) PullRequest {
+	pr := PullRequest{Stat: stat}
+	groups := make(map[string]*PRGroup)
+	var order []string // Types in first-seen order
+	var headers []Header
+	issues := make(map[string]int) // Key -> index in pr.Issues
+
+	for _, c := range commits {
+		if IsWorkInProgress(c.Message) {
+			continue
+		}
+		pr.Commits++
+
+		h, ok := ParseHeader(c.Message)
+		typ := ""
+		if ok {
+			typ = h.Type
+			headers = append(headers, h)
+		}
+		g, seen := groups[typ]
+		if !seen {
+			g = &PRGroup{Type: typ, Title: groupTitle(typ)}
+			groups[typ] = g
+			order = append(order, typ)
+		}
+		g.Changes = append(g.Changes, PRChange{Scope: h.Scope, Description: h.Description, ShortHash: c.ShortHash})
+
+		pr.Breaking = append(pr.Breaking, breakingChanges(h, c.Message)...)
+
+		closing := make(map[string]bool)
+		for _, t := range Trailers(c.Message) {
+			for _, key := range closingKeys {
+				if strings.EqualFold(t[0], key) {
+					for _, issue := range ExtractIssues(t[1], issuePattern) {
+						closing[issue] = true
+					}
+				}
+			}
+		}
+		for _, key := range ExtractIssues(c.Message, issuePattern) {
+			if i, ok := issues[key]; ok {
+				pr.Issues[i].Closes = pr.Issues[i].Closes || closing[key]
+				continue
+			}
+			issues[key] = len(pr.Issues)
+			pr.Issues = append(pr.Issues, PRIssue{Key: key, Closes: closing[key]})
+		}
+	}
+
+	// Groups in the order of Types, then unknown types as first seen, other
+	// changes last.
+	for _, t := range Types {
+		if g, ok := groups[t]; ok {
+			pr.Groups = append(pr.Groups, *g)
+		}
+	}
+	for _, t := range order {
+		if t != "" && !isType(t) {
+			pr.Groups = append(pr.Groups, *groups[t])
+		}
+	}
+	if g, ok := groups[""]; ok {
+		pr.Groups = append(pr.Groups, *g)
+	}
+
+	pr.Title = prTitle(commits, headers)
+	return pr
+}
+
+// groupTitle returns the section title of a type.
+func groupTitle(typ string) string {
+	if title, ok := typeTitles[typ]; ok {
+		return title
+	}
+	if typ == "" {
+		return "Other Changes"
+	}
+	return typ
+}
+
+// breakingChanges returns the breaking changes a commit announces, from its
+// BREAKING CHANGE footer or else its "!" header.
+func breakingChanges(h Header, message string) []string {
+	var changes []string
+	for _, line := range strings.Split(message, "\n") {
+		if m := breakingRe.FindStringSubmatch(strings.TrimSpace(line)); m != nil {
+			changes = append(changes, m[1])
+		}
+	}
+	if len(changes) == 0 && h.Breaking {
+		changes = append(changes, h.Description)
+	}
+	return changes
+}
+
+// prTitle is the subject of the only commit, or a header made from the most
+// common type: its shared scope, if any, and the oldest description. When
+// every commit is WIP or fixup!, the oldest subject without its fixup!
+// prefixes is used.
+func prTitle(commits []PRCommit, headers []Header) string {
+	if len(commits) == 1 || len(headers) == 0 {
+		for _, c := range commits {
+			if !IsWorkInProgress(c.Message) {
+				return subjectOf(c.Message)
+			}
+		}
+		if len(commits) > 0 {
+			return trimAutosquash(subjectOf(commits[0].Message))
+		}
+		return ""
+	}
+
+	counts := make(map[string]int)
+	for _, h := range headers {
+		counts[h.Type]++
+	}
+	best := ""
+	for _, t := range Types { // Ties go to the earlier type: feat before fix
+		if counts[t] > counts[best] {
+			best = t
+		}
+	}
+	if best == "" { // Only unknown types
+		best = headers[0].Type
+	}
+
+	var title Header
+	scopes := make(map[string]bool)
+	for _, h := range headers {
+		if h.Type != best {
+			continue
+		}
+		if title.Type == "" {
+			title = Header{Type: h.Type, Description: h.Description}
+		}
+		scopes[h.Scope] = true
+	}
+	if len(scopes) == 1 {
+		for s := range scopes {
+			title.Scope = s
+		}
+	}
+	return title.String()
+}
+
+// Summary is the one-line overview, e.g. "3 commits, 5 files changed (+120 -8)".
+func (pr PullRequest) Summary() string {
+	commits := "commits"
+	if pr.Commits == 1 {
+		commits = "commit"
+	}
+	files := "files"
+	if pr.Stat.Files == 1 {
+		files = "file"
+	}
+	return fmt.Sprintf("%d %s, %d %s changed (+%d -%d)", pr.Commits, commits, pr.Stat.Files, files, pr.Stat.Added, pr.Stat.Deleted)
+}
+
+// ChangesMarkdown lists the changes under one heading per type.
+func (pr PullRequest) ChangesMarkdown() string {
+	var b strings.Builder
+	for i, g := range pr.Groups {
+		if i > 0 {
+			b.WriteString("\n")
+		}
+		fmt.Fprintf(&b, "### %s\n\n", g.Title)
+		for _, c := range g.Changes {
+			b.WriteString("- ")
+			if c.Scope != "" {
+				fmt.Fprintf(&b, "**%s**: ", c.Scope)
+			}
+			fmt.Fprintf(&b, "%s (%s)\n", c.Description, c.ShortHash)
+		}
+	}
+	return strings.TrimSpace(b.String())
+}
+
+// BreakingMarkdown lists the breaking changes, or "" if there are none.
+func (pr PullRequest) BreakingMarkdown() string {
+	return bullets(pr.Breaking)
+}
+
+// IssuesMarkdown lists the referenced issues, closed ones first, or "".
+func (pr PullRequest) IssuesMarkdown() string {
+	var closes, refs []string
+	for _, issue := range pr.Issues {
+		if issue.Closes {
+			closes = append(closes, "Closes "+issue.Key)
+		} else {
+			refs = append(refs, "Refs "+issue.Key)
+		}
+	}
+	return bullets(append(closes, refs...))
+}
+
+// bullets renders a Markdown list.
+func bullets(items []string) string {
+	var lines []string
+	for _, item := range items {
+		lines = append(lines, "- "+item)
+	}
+	return strings.Join(lines, "\n")
+}
+
+// Markdown renders the body: summary, changes, breaking changes and issues.
+func (pr PullRequest) Markdown() string {
+	sections := []string{"## Summary\n\n" + pr.Summary()}
+	if changes := pr.ChangesMarkdown(); changes != "" {
+		sections = append(sections, "## Changes\n\n"+changes)
+	}
+	if breaking := pr.BreakingMarkdown(); breaking != "" {
+		sections = append(sections, "## Breaking Changes\n\n"+breaking)
+	}
+	if issues := pr.IssuesMarkdown(); issues != "" {
+		sections = append(sections, "## Issues\n\n"+issues)
+	}
+	return strings.Join(sections, "\n\n")
+}
+
+// Render fills a pull request template. The placeholders {{title}},
+// {{summary}}, {{changes}}, {{breaking}}, {{issues}} and {{body}} (the whole
+// generated body) are replaced. A template without placeholders, such as a
+// checklist, is appended to the generated body; without a template the body
+// is returned as is.
+func (pr PullRequest) Render(template string) string {
+	template = strings.TrimSpace(template)
+	if template == "" {
+		return pr.Markdown()
+	}
+	if !strings.Contains(template, "{{") {
+		return pr.Markdown() + "\n\n" + template
+	}
+	return strings.NewReplacer(
+		"{{title}}", pr.Title,
+		"{{summary}}", pr.Summary(),
+		"{{changes}}", pr.ChangesMarkdown(),
+		"{{breaking}}", orNone(pr.BreakingMarkdown()),
+		"{{issues}}", orNone(pr.IssuesMarkdown()),
+		"{{body}}", pr.Markdown(),
+	).Replace(template)
+}
+
+// orNone stands in for an empty template section.
+func orNone(s string) string {
+	if s == "" {
+		return "None"
+	}
+	return s
+}
@@ -0,0 +1,88 @@
+package analysis
+
+import (
+	"regexp"
+	"strings"
+	"testing"
+)
+
+func TestDescribePR(t *testing.T) {
+	commits := []PRCommit{
+		{"a1", "feat(ui): add diff pane\n\nCloses: ABC-42"},
+		{"b2", "fix(ui): crash on empty repo\n\nRefs: #7"},
+		{"c3", "fixup! feat(ui): add diff pane"},
+		{"d4", "feat(ui)!: drop the legacy view\n\nBREAKING CHANGE: the --legacy flag is gone\n\nRefs: ABC-42"},
+		{"e5", "update readme"},
+	}
+	pr := DescribePR(commits, DiffStat{Files: 3, Added: 40, Deleted: 5}, regexp.MustCompile(DefaultIssuePattern))
+
+	if pr.Title != "feat(ui): add diff pane" {
+		t.Errorf("Title = %q", pr.Title)
+	}
+	if pr.Commits != 4 {
+		t.Errorf("Commits = %d, want 4 (fixup left out)", pr.Commits)
+	}
+	if len(pr.Groups) != 3 || pr.Groups[0].Title != "Features" || pr.Groups[1].Title != "Bug Fixes" || pr.Groups[2].Title != "Other Changes" {
+		t.Errorf("Groups = %+v", pr.Groups)
+	}
+	if len(pr.Breaking) != 1 || pr.Breaking[0] != "the --legacy flag is gone" {
+		t.Errorf("Breaking = %v", pr.Breaking)
+	}
+	if want := "- Closes ABC-42\n- Refs #7"; pr.IssuesMarkdown() != want {
+		t.Errorf("IssuesMarkdown() = %q, want %q", pr.IssuesMarkdown(), want)
+	}
+
+	body := pr.Markdown()
+	for _, want := range []string{
+		"## Summary\n\n4 commits, 3 files changed (+40 -5)",
+		"### Features\n\n- **ui**: add diff pane (a1)\n- **ui**: drop the legacy view (d4)",
+		"### Other Changes\n\n- update readme (e5)",
+		"## Breaking Changes\n\n- the --legacy flag is gone",
+	} {
+		if !strings.Contains(body, want) {
+			t.Errorf("Markdown() misses %q:\n%s", want, body)
+		}
+	}
+}
+
+func TestDescribePRGroupOrder(t *testing.T) {
+	commits := []PRCommit{
+		{"a1", "wip2: z"}, {"b2", "misc"}, {"c3", "infra: y"}, {"d4", "fix: x"}, {"e5", "wip2: w"},
+	}
+	for i := 0; i < 10; i++ {
+		pr := DescribePR(commits, DiffStat{}, regexp.MustCompile(DefaultIssuePattern))
+		var types []string
+		for _, g := range pr.Groups {
+			types = append(types, g.Type)
+		}
+		if got := strings.Join(types, ","); got != "fix,wip2,infra," {
+			t.Fatalf("group types = %q, want known types, unknown as first seen, then other", got)
+		}
+	}
+}
+
+func TestPRTitleAndTemplate(t *testing.T) {
+	pattern := regexp.MustCompile(DefaultIssuePattern)
+	one := DescribePR([]PRCommit{{"a1", "docs: explain sync"}}, DiffStat{Files: 1, Added: 2}, pattern)
+	if one.Title != "docs: explain sync" {
+		t.Errorf("single commit Title = %q", one.Title)
+	}
+	mixed := DescribePR([]PRCommit{{"a1", "fix(api): a"}, {"b2", "fix(cli): b"}, {"c3", "feat(api): c"}}, DiffStat{}, pattern)
+	if mixed.Title != "fix: a" {
+		t.Errorf("mixed Title = %q, want the most common type without a shared scope", mixed.Title)
+	}
+	fixups := DescribePR([]PRCommit{{"a1", "fixup! fixup! feat(ui): diff pane"}, {"b2", "wip"}}, DiffStat{}, pattern)
+	if fixups.Title != "feat(ui): diff pane" {
+		t.Errorf("fixup-only Title = %q, want the first subject without fixup!", fixups.Title)
+	}
+	if wip := DescribePR([]PRCommit{{"a1", "WIP: parser"}}, DiffStat{}, pattern); wip.Title != "WIP: parser" {
+		t.Errorf("WIP-only Title = %q, want the first subject", wip.Title)
+	}
+
+	if got := one.Render("## What\n\n{{changes}}\n\n## Issues\n\n{{issues}}"); got != "## What\n\n### Documentation\n\n- explain sync (a1)\n\n## Issues\n\nNone" {
+		t.Errorf("Render() with placeholders = %q", got)
+	}
+	if got := one.Render("- [ ] Tests pass"); !strings.HasPrefix(got, "## Summary") || !strings.HasSuffix(got, "\n\n- [ ] Tests pass") {
+		t.Errorf("Render() without placeholders = %q", got)
+	}
+}
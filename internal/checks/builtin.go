@@ -0,0 +1,223 @@
+package checks
+
+import (
+	"bytes"
+	"fmt"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"raven/internal/analysis"
+	"raven/internal/git"
+)
+
+// Names are the built-in checks.
+var Names = []string{"whitespace", "conflict", "size", "secrets", "gofmt", "vet"}
+
+// Defaults are the built-in checks run when none are configured. vet is left
+// out: it builds packages, which is slow on large repositories. size is left
+// out: raven's large-file guard handles large files before the checks run.
+var Defaults = []string{"whitespace", "conflict", "secrets", "gofmt"}
+
+// DefaultMaxSize is the largest staged file the size check accepts.
+const DefaultMaxSize = 5 << 20 // 5 MB
+
+// Options configures the built-in checks.
+type Options struct {
+	MaxSize       int64     // Bytes; 0 means DefaultMaxSize
+	MaxBinarySize int64     // Bytes; 0 means DefaultMaxBinarySize
+	Allowlist     Allowlist // Paths and values the secrets check accepts
+}
+
+// Builtin returns the built-in check with the given name.
+func Builtin(name string, opts Options) (Check, bool) {
+	var run func(Staged) ([]Problem, error)
+	switch name {
+	case "whitespace":
+		run = checkWhitespace
+	case "conflict":
+		run = checkConflictMarkers
+	case "size":
+		run = func(s Staged) ([]Problem, error) { return checkSize(s, opts), nil }
+	case "secrets":
+		run = func(s Staged) ([]Problem, error) { return ScanSecrets(s, opts.Allowlist), nil }
+	case "gofmt":
+		run = checkGofmt
+	case "vet":
+		run = checkVet
+	default:
+		return Check{}, false
+	}
+	return Check{Name: name, Run: run}, true
+}
+
+// checkWhitespace finds added lines ending in spaces or tabs. Markdown is
+// skipped: two trailing spaces are a line break there.
+func checkWhitespace(s Staged) ([]Problem, error) {
+	var problems []Problem
+	for _, f := range s.Diff {
+		if ext := strings.ToLower(path.Ext(f.Path)); ext == ".md" || ext == ".markdown" {
+			continue
+		}
+		for _, h := range f.Hunks {
+			for _, l := range h.Added {
+				text := strings.TrimSuffix(l.Text, "\r")
+				if strings.TrimRight(text, " \t") != text {
+					problems = append(problems, Problem{File: f.Path, Line: l.Number, Message: "trailing whitespace"})
+				}
+			}
+		}
+	}
+	return problems, nil
+}
+
+// checkConflictMarkers finds merge conflict markers left in added lines.
+func checkConflictMarkers(s Staged) ([]Problem, error) {
+	var problems []Problem
+	for _, f := range s.Diff {
+		for _, h := range f.Hunks {
+			for _, l := range h.Added {
+				text := strings.TrimSuffix(l.Text, "\r")
+				if strings.HasPrefix(text, "<<<<<<< ") || strings.HasPrefix(text, ">>>>>>> ") || text == "=======" {
+					problems = append(problems, Problem{File: f.Path, Line: l.Number, Message: "merge conflict marker"})
+				}
+			}
+		}
+	}
+	return problems, nil
+}
+
+// checkSize finds staged files over their size limit.
+func checkSize(s Staged, opts Options) []Problem {
+	var problems []Problem
+	for _, f := range LargeFiles(s, opts) {
+		problems = append(problems, Problem{File: f.Path, Message: "too large: " + f.Reason()})
+	}
+	return problems
+}
+
+// goFiles returns the staged Go files.
+func goFiles(s Staged) []string {
+	var files []string
+	for _, f := range s.Files {
+		if strings.HasSuffix(f, ".go") {
+			files = append(files, f)
+		}
+	}
+	return files
+}
+
+// checkGofmt finds staged Go files whose staged content is not gofmt-formatted.
+func checkGofmt(s Staged) ([]Problem, error) {
+	files := goFiles(s)
+	if len(files) == 0 {
+		return nil, nil
+	}
+	if _, err := exec.LookPath("gofmt"); err != nil {
+		return nil, Skipped{Reason: "gofmt not found"}
+	}
+	var problems []Problem
+	for _, f := range files {
+		content, err := git.StagedContent(f)
+		if err != nil {
+			continue
+		}
+		cmd := exec.Command("gofmt", "-l")
+		cmd.Stdin = bytes.NewReader(content)
+		out, err := cmd.CombinedOutput()
+		switch {
+		case err != nil:
+			problems = append(problems, Problem{File: f, Message: "does not parse: " + analysis.FirstLine(strings.TrimSpace(string(out)))})
+		case len(bytes.TrimSpace(out)) > 0:
+			problems = append(problems, Problem{File: f, Message: "not formatted (run gofmt -w)"})
+		}
+	}
+	return problems, nil
+}
+
+var vetLineRe = regexp.MustCompile(`^(?:vet: )?(.+?\.go):(\d+)(?::\d+)?: (.+)$`)
+
+// checkVet runs go vet on the packages of the staged Go files. It sees the
+// working tree, not only the staged content.
+func checkVet(s Staged) ([]Problem, error) {
+	files := goFiles(s)
+	if len(files) == 0 {
+		return nil, nil
+	}
+	if _, err := exec.LookPath("go"); err != nil {
+		return nil, Skipped{Reason: "go not found"}
+	}
+	root, err := git.TopLevel()
+	if err != nil {
+		return nil, err
+	}
+	seen := make(map[string]bool)
+	var pkgs []string
+	for _, f := range files {
+		dir := "./" + filepath.ToSlash(filepath.Dir(f))
+		if !seen[dir] {
+			seen[dir] = true
+			pkgs = append(pkgs, dir)
+		}
+	}
+	sort.Strings(pkgs)
+
+	cmd := exec.Command("go", append([]string{"vet"}, pkgs...)...)
+	cmd.Dir = root
+	out, err := cmd.CombinedOutput()
+	if err == nil {
+		return nil, nil
+	}
+	var problems []Problem
+	for _, line := range strings.Split(strings.TrimSpace(string(out)), "\n") {
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue // Package headers
+		}
+		if m := vetLineRe.FindStringSubmatch(line); m != nil {
+			n, _ := strconv.Atoi(m[2])
+			problems = append(problems, Problem{File: strings.TrimPrefix(m[1], "./"), Line: n, Message: m[3]})
+		} else {
+			problems = append(problems, Problem{Message: strings.TrimSpace(line)})
+		}
+	}
+	return problems, nil
+}
+
+// ParseSize parses a size such as "5MB", "200k" or "1048576" (bytes).
+// Units are powers of 1024.
+func ParseSize(size string) (int64, error) {
+	s := strings.ToUpper(strings.TrimSpace(size))
+	units := []struct {
+		suffix string
+		factor int64
+	}{{"GB", 1 << 30}, {"MB", 1 << 20}, {"KB", 1 << 10}, {"G", 1 << 30}, {"M", 1 << 20}, {"K", 1 << 10}, {"B", 1}}
+	factor := int64(1)
+	for _, u := range units {
+		if strings.HasSuffix(s, u.suffix) {
+			s, factor = strings.TrimSpace(strings.TrimSuffix(s, u.suffix)), u.factor
+			break
+		}
+	}
+	n, err := strconv.ParseFloat(s, 64)
+	if err != nil || n < 0 {
+		return 0, fmt.Errorf("invalid size %q", size)
+	}
+	return int64(n * float64(factor)), nil
+}
+
+// FormatSize formats a size in bytes, e.g. "4.2 MB".
+func FormatSize(n int64) string {
+	switch {
+	case n >= 1<<30:
+		return fmt.Sprintf("%.1f GB", float64(n)/(1<<30))
+	case n >= 1<<20:
+		return fmt.Sprintf("%.1f MB", float64(n)/(1<<20))
+	case n >= 1<<10:
+		return fmt.Sprintf("%.1f KB", float64(n)/(1<<10))
+	}
+	return fmt.Sprintf("%d B", n)
+}
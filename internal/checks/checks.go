@@ -0,0 +1,155 @@
+// Package checks runs pre-commit checks on the staged changes: built-in ones
+// (whitespace, conflict markers, file size, secrets, gofmt, go vet) and
+// custom commands.
+package checks
+
+import (
+	"errors"
+	"fmt"
+	"os/exec"
+	"strings"
+	"sync"
+
+	"raven/internal/analysis"
+	"raven/internal/git"
+)
+
+// Problem is one finding of a check. Line is 0 when it concerns a whole file,
+// File is "" when it concerns no file in particular.
+type Problem struct {
+	File    string
+	Line    int
+	Message string
+}
+
+// String formats the problem as "file:line: message".
+func (p Problem) String() string {
+	switch {
+	case p.File == "":
+		return p.Message
+	case p.Line == 0:
+		return p.File + ": " + p.Message
+	}
+	return fmt.Sprintf("%s:%d: %s", p.File, p.Line, p.Message)
+}
+
+// Staged is what the checks inspect.
+type Staged struct {
+	Files []string            // Added, copied, modified or renamed files
+	Diff  []analysis.FileDiff // The staged diff, for the added lines
+}
+
+// Check inspects the staged changes. It returns an error when it could not
+// run at all, or Skipped when it should not block the commit anyway, e.g.
+// because gofmt is not installed. The problems of a Warn check are reported
+// without blocking the commit.
+type Check struct {
+	Name string
+	Run  func(Staged) ([]Problem, error)
+	Warn bool
+}
+
+// Skipped is returned by a check that could not run for a reason that must
+// not block the commit, such as a missing tool. It is reported as a warning.
+type Skipped struct {
+	Reason string
+}
+
+func (s Skipped) Error() string {
+	return "skipped, " + s.Reason
+}
+
+// Result is the outcome of a check.
+type Result struct {
+	Name     string
+	Problems []Problem
+	Err      error
+	Warn     bool
+}
+
+// Failed reports whether the check found problems or could not run.
+func (r Result) Failed() bool {
+	return len(r.Problems) > 0 || r.Err != nil
+}
+
+// Blocking reports whether the result stops the commit.
+func (r Result) Blocking() bool {
+	return r.Failed() && !r.Warn
+}
+
+// Lines formats the failure for display, one line per problem.
+func (r Result) Lines() []string {
+	var lines []string
+	if r.Err != nil {
+		lines = append(lines, r.Name+": "+r.Err.Error())
+	}
+	for _, p := range r.Problems {
+		lines = append(lines, r.Name+": "+p.String())
+	}
+	return lines
+}
+
+// LoadStaged reads the staged files and diff.
+func LoadStaged() (Staged, error) {
+	files, err := git.StagedFiles()
+	if err != nil {
+		return Staged{}, err
+	}
+	diff, err := git.GetStagedDiff()
+	if err != nil {
+		return Staged{}, err
+	}
+	return Staged{Files: files, Diff: analysis.ParseDiff(diff)}, nil
+}
+
+// Run runs a check.
+func Run(c Check, s Staged) Result {
+	problems, err := c.Run(s)
+	var skipped Skipped
+	return Result{Name: c.Name, Problems: problems, Err: err, Warn: c.Warn || errors.As(err, &skipped)}
+}
+
+// RunAll runs the checks in parallel and returns their results in order.
+func RunAll(checks []Check, s Staged) []Result {
+	results := make([]Result, len(checks))
+	var wg sync.WaitGroup
+	for i, c := range checks {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			results[i] = Run(c, s)
+		}()
+	}
+	wg.Wait()
+	return results
+}
+
+// Command returns a check running a shell command with the staged files as
+// its arguments ("$@"). It fails when the command exits non-zero; each line
+// of its output is a problem.
+func Command(command string) Check {
+	name := command
+	if fields := strings.Fields(command); len(fields) > 0 {
+		name = fields[0]
+	}
+	return Check{Name: name, Run: func(s Staged) ([]Problem, error) {
+		cmd := exec.Command("sh", append([]string{"-c", command, "raven"}, s.Files...)...)
+		out, err := cmd.CombinedOutput()
+		if err == nil {
+			return nil, nil
+		}
+		if _, ok := err.(*exec.ExitError); !ok {
+			return nil, err
+		}
+		var problems []Problem
+		for _, line := range strings.Split(strings.TrimSpace(string(out)), "\n") {
+			if line = strings.TrimSpace(line); line != "" {
+				problems = append(problems, Problem{Message: line})
+			}
+		}
+		if len(problems) == 0 {
+			problems = append(problems, Problem{Message: err.Error()})
+		}
+		return problems, nil
+	}}
+}
@@ -0,0 +1,72 @@
+package checks
+
+import (
+	"fmt"
+	"path"
+
+	"raven/internal/git"
+)
+
+// DefaultMaxBinarySize is the largest staged binary file accepted. Binaries
+// do not diff or merge, so every version stays in the history as is.
+const DefaultMaxBinarySize = 1 << 20 // 1 MB
+
+// LargeFile is a staged file over its size limit.
+type LargeFile struct {
+	Path   string
+	Size   int64
+	Binary bool
+	Limit  int64 // The limit it exceeds
+}
+
+// Reason explains why the file is flagged, e.g. "binary, 2.0 MB (limit 1.0 MB)".
+func (f LargeFile) Reason() string {
+	kind := "text"
+	if f.Binary {
+		kind = "binary"
+	}
+	return fmt.Sprintf("%s, %s (limit %s)", kind, FormatSize(f.Size), FormatSize(f.Limit))
+}
+
+// LargeFiles returns the staged files larger than opts.MaxSize, and the
+// binary ones larger than opts.MaxBinarySize.
+func LargeFiles(s Staged, opts Options) []LargeFile {
+	limit, binaryLimit := opts.MaxSize, opts.MaxBinarySize
+	if limit <= 0 {
+		limit = DefaultMaxSize
+	}
+	if binaryLimit <= 0 {
+		binaryLimit = DefaultMaxBinarySize
+	}
+	binary := make(map[string]bool)
+	for _, f := range s.Diff {
+		if f.Binary {
+			binary[f.Path] = true
+		}
+	}
+
+	var large []LargeFile
+	for _, f := range s.Files {
+		size, err := git.StagedSize(f)
+		if err != nil {
+			continue // A submodule or a file unstaged meanwhile
+		}
+		max := limit
+		if binary[f] && binaryLimit < max {
+			max = binaryLimit
+		}
+		if size > max {
+			large = append(large, LargeFile{Path: f, Size: size, Binary: binary[f], Limit: max})
+		}
+	}
+	return large
+}
+
+// LFSPattern is the git LFS pattern tracking a file: all files with its
+// extension ("*.zip"), or the file itself when it has none.
+func LFSPattern(file string) string {
+	if ext := path.Ext(file); ext != "" && ext != path.Base(file) {
+		return "*" + ext
+	}
+	return file
+}
@@ -0,0 +1,57 @@
+package checks
+
+import (
+	"bytes"
+	"os"
+	"os/exec"
+	"testing"
+)
+
+func TestLargeFiles(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not installed")
+	}
+	t.Chdir(t.TempDir())
+	t.Setenv("GIT_CONFIG_GLOBAL", "/dev/null")
+	if out, err := exec.Command("git", "init", "-q").CombinedOutput(); err != nil {
+		t.Fatalf("git init: %v\n%s", err, out)
+	}
+	files := map[string][]byte{
+		"notes.txt": bytes.Repeat([]byte("text\n"), 40), // 200 B of text
+		"logo.png":  append([]byte{0x89, 'P', 'N', 'G', 0}, make([]byte, 95)...),
+		"small.bin": {0, 1, 2},
+		"main.go":   []byte("package main\n"),
+	}
+	for name, data := range files {
+		if err := os.WriteFile(name, data, 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if out, err := exec.Command("git", "add", ".").CombinedOutput(); err != nil {
+		t.Fatalf("git add: %v\n%s", err, out)
+	}
+
+	staged, err := LoadStaged()
+	if err != nil {
+		t.Fatal(err)
+	}
+	large := LargeFiles(staged, Options{MaxSize: 150, MaxBinarySize: 50})
+	if len(large) != 2 {
+		t.Fatalf("LargeFiles() = %+v, want logo.png and notes.txt", large)
+	}
+	if f := large[0]; f.Path != "logo.png" || !f.Binary || f.Limit != 50 || f.Reason() != "binary, 100 B (limit 50 B)" {
+		t.Errorf("large[0] = %+v (%s)", f, f.Reason())
+	}
+	if f := large[1]; f.Path != "notes.txt" || f.Binary || f.Limit != 150 {
+		t.Errorf("large[1] = %+v", f)
+	}
+}
+
+func TestLFSPattern(t *testing.T) {
+	tests := map[string]string{"build/app.zip": "*.zip", "assets/model": "assets/model", ".bashrc": ".bashrc"}
+	for file, want := range tests {
+		if got := LFSPattern(file); got != want {
+			t.Errorf("LFSPattern(%q) = %q, want %q", file, got, want)
+		}
+	}
+}
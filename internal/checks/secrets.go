@@ -0,0 +1,242 @@
+package checks
+
+import (
+	"math"
+	"os"
+	"path"
+	"regexp"
+	"strings"
+)
+
+// DefaultAllowlistFile is the allowlist read from the repository root.
+const DefaultAllowlistFile = ".secrets-allowlist"
+
+// AllowComment on a line marks whatever it contains as not a secret.
+const AllowComment = "raven:allow-secret"
+
+// secretRules match well-known credential formats. group is the submatch
+// holding the secret itself, 0 for the whole match.
+var secretRules = []struct {
+	name  string
+	re    *regexp.Regexp
+	group int
+}{
+	{"private key", regexp.MustCompile(`-----BEGIN ([A-Z]+ )*PRIVATE KEY( BLOCK)?-----`), 0},
+	{"AWS access key", regexp.MustCompile(`\b(AKIA|ASIA)[0-9A-Z]{16}\b`), 0},
+	{"AWS secret key", regexp.MustCompile(`(?i)aws.{0,20}secret.{0,20}[:=]\s*["']?([A-Za-z0-9/+]{40})\b`), 1},
+	{"GitHub token", regexp.MustCompile(`\b(gh[pousr]_[A-Za-z0-9]{36,}|github_pat_[A-Za-z0-9_]{60,})\b`), 0},
+	{"GitLab token", regexp.MustCompile(`\bglpat-[A-Za-z0-9_-]{20,}\b`), 0},
+	{"Slack token", regexp.MustCompile(`\bxox[abposr]-[A-Za-z0-9-]{10,}\b`), 0},
+	{"Stripe key", regexp.MustCompile(`\b[sr]k_live_[A-Za-z0-9]{20,}\b`), 0},
+	{"Google API key", regexp.MustCompile(`\bAIza[0-9A-Za-z_-]{35}\b`), 0},
+	{"JSON web token", regexp.MustCompile(`\beyJ[A-Za-z0-9_-]{10,}\.eyJ[A-Za-z0-9_-]{10,}\.[A-Za-z0-9_-]{10,}`), 0},
+	{"credentials in URL", regexp.MustCompile(`[a-z][a-z0-9+.-]*://[^/\s:@]+:([^/\s:@]{6,})@`), 1},
+	{"hard-coded secret", regexp.MustCompile(`(?i)\b(api[_-]?key|secret|passw(or)?d|token)\b\s*[:=]\s*["']([^"'\s]{8,})["']`), 3},
+}
+
+// valueRe finds values assigned or quoted in a line, the candidates for the
+// entropy test.
+var valueRe = regexp.MustCompile(`[=:(,]\s*["'` + "`" + `]?([A-Za-z0-9+/_\-]{20,}={0,2})`)
+
+// hexRe matches hashes (commit ids, checksums), which are random but public.
+var hexRe = regexp.MustCompile(`^[0-9a-fA-F]+$`)
+
+// minEntropy is the Shannon entropy (bits per character) above which an
+// assigned value looks like a generated key rather than a word or identifier.
+const minEntropy = 4.2
+
+// forbiddenFiles are file names (globs on the base name) that hold
+// credentials and should never be committed.
+var forbiddenFiles = []string{".env", ".env.*", "*.pem", "*.key", "*.p12", "*.pfx", "id_rsa", "id_dsa", "id_ecdsa", "id_ed25519", ".netrc", ".pgpass", "credentials.json"}
+
+// safeEnvFiles are the templates of .env files, meant to be committed.
+var safeEnvFiles = []string{".env.example", ".env.sample", ".env.template", ".env.dist"}
+
+// skippedFiles are generated files full of hashes, not scanned for entropy.
+var skippedFiles = []string{"go.sum", "package-lock.json", "yarn.lock", "pnpm-lock.yaml", "Cargo.lock", "poetry.lock", "composer.lock", "Gemfile.lock"}
+
+// Allowlist holds what the secrets check accepts: paths that are not scanned
+// and values that are known not to be secrets.
+type Allowlist struct {
+	Paths  []string // Globs; "dir/" or "dir/**" covers a whole directory
+	Values []string
+}
+
+// ParseAllowlist reads an allowlist: one path glob per line, or "value:"
+// followed by a value that is not a secret (e.g. a key from the docs).
+// Blank lines and lines starting with "#" are ignored.
+func ParseAllowlist(text string) Allowlist {
+	var a Allowlist
+	for _, line := range strings.Split(text, "\n") {
+		line = strings.TrimSpace(line)
+		switch {
+		case line == "" || strings.HasPrefix(line, "#"):
+		case strings.HasPrefix(line, "value:"):
+			if v := strings.TrimSpace(strings.TrimPrefix(line, "value:")); v != "" {
+				a.Values = append(a.Values, v)
+			}
+		default:
+			a.Paths = append(a.Paths, line)
+		}
+	}
+	return a
+}
+
+// LoadAllowlist reads an allowlist file. A missing file is an empty allowlist.
+func LoadAllowlist(file string) (Allowlist, error) {
+	data, err := os.ReadFile(file)
+	if os.IsNotExist(err) {
+		return Allowlist{}, nil
+	}
+	if err != nil {
+		return Allowlist{}, err
+	}
+	return ParseAllowlist(string(data)), nil
+}
+
+// allowsPath reports whether a file is not to be scanned.
+func (a Allowlist) allowsPath(file string) bool {
+	for _, p := range a.Paths {
+		dir := strings.TrimSuffix(strings.TrimSuffix(p, "**"), "/")
+		if (strings.HasSuffix(p, "/") || strings.HasSuffix(p, "/**")) && strings.HasPrefix(file, dir+"/") {
+			return true
+		}
+		if matchGlob(p, file) {
+			return true
+		}
+	}
+	return false
+}
+
+// allowsValue reports whether a secret is a known non-secret value, or part
+// of one (e.g. the password of a listed URL). A listed value that is only
+// part of the secret does not cover it, so "test" never hides a real key.
+func (a Allowlist) allowsValue(secret string) bool {
+	for _, v := range a.Values {
+		if strings.Contains(v, secret) {
+			return true
+		}
+	}
+	return false
+}
+
+// matchGlob matches a glob against a path, or against its base name when the
+// glob has no slash.
+func matchGlob(glob, file string) bool {
+	if ok, _ := path.Match(glob, file); ok {
+		return true
+	}
+	if !strings.Contains(glob, "/") {
+		ok, _ := path.Match(glob, path.Base(file))
+		return ok
+	}
+	return false
+}
+
+// ScanSecrets looks for credentials in the staged changes: forbidden file
+// names, well-known key formats and high-entropy values in added lines.
+// Lines containing AllowComment and whatever the allowlist covers are skipped.
+func ScanSecrets(s Staged, allow Allowlist) []Problem {
+	var problems []Problem
+	for _, f := range s.Files {
+		if !allow.allowsPath(f) && isForbiddenFile(f) {
+			problems = append(problems, Problem{File: f, Message: "file likely holds credentials; unstage it (git restore --staged " + f + ") and add it to .gitignore"})
+		}
+	}
+
+	for _, f := range s.Diff {
+		if f.Binary || allow.allowsPath(f.Path) {
+			continue
+		}
+		entropy := !isSkippedFile(f.Path)
+		for _, h := range f.Hunks {
+			for _, l := range h.Added {
+				if strings.Contains(l.Text, AllowComment) {
+					continue
+				}
+				if name, secret := findSecret(l.Text, entropy); name != "" && !allow.allowsValue(secret) {
+					problems = append(problems, Problem{File: f.Path, Line: l.Number, Message: "possible " + name + " (" + mask(secret) + ")"})
+				}
+			}
+		}
+	}
+	return problems
+}
+
+// findSecret returns the kind and value of the first secret in a line, or
+// "" if there is none. entropy enables the test for random-looking values.
+func findSecret(line string, entropy bool) (name, secret string) {
+	for _, r := range secretRules {
+		if m := r.re.FindStringSubmatch(line); m != nil {
+			return r.name, m[r.group]
+		}
+	}
+	if !entropy {
+		return "", ""
+	}
+	for _, m := range valueRe.FindAllStringSubmatch(line, -1) {
+		v := m[1]
+		if !hexRe.MatchString(v) && hasLetterAndDigit(v) && shannonEntropy(v) >= minEntropy {
+			return "high-entropy secret", v
+		}
+	}
+	return "", ""
+}
+
+// isForbiddenFile reports whether a file name is one that holds credentials.
+func isForbiddenFile(file string) bool {
+	base := path.Base(file)
+	for _, safe := range safeEnvFiles {
+		if base == safe {
+			return false
+		}
+	}
+	for _, glob := range forbiddenFiles {
+		if ok, _ := path.Match(glob, base); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// isSkippedFile reports whether a file is a lock file, full of hashes.
+func isSkippedFile(file string) bool {
+	base := path.Base(file)
+	for _, name := range skippedFiles {
+		if base == name {
+			return true
+		}
+	}
+	return false
+}
+
+// hasLetterAndDigit reports whether s mixes letters and digits, as generated
+// keys do and identifiers mostly do not.
+func hasLetterAndDigit(s string) bool {
+	letter := strings.IndexFunc(s, func(r rune) bool { return r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' }) >= 0
+	digit := strings.IndexFunc(s, func(r rune) bool { return r >= '0' && r <= '9' }) >= 0
+	return letter && digit
+}
+
+// shannonEntropy returns the entropy of s in bits per character.
+func shannonEntropy(s string) float64 {
+	counts := make(map[rune]int)
+	for _, r := range s {
+		counts[r]++
+	}
+	n := float64(len(s))
+	var e float64
+	for _, c := range counts {
+		p := float64(c) / n
+		e -= p * math.Log2(p)
+	}
+	return e
+}
+
+// mask hides the middle of a secret so the report does not leak it again.
+func mask(secret string) string {
+	if len(secret) <= 8 {
+		return strings.Repeat("*", len(secret))
+	}
+	return secret[:4] + "…" + secret[len(secret)-4:]
+}
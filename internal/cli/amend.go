@@ -7,6 +7,7 @@ import (
 	"strings"
 
 	"raven/internal/git"
+	"raven/internal/undo"
 
 	"github.com/spf13/cobra"
 )
@@ -40,10 +41,19 @@ var amendCmd = &cobra.Command{
 		// We pass empty diff (not needed since we provide override).
 		// We pass empty manualMessage (unless we want to support -m here too? Nah, interactive default).
 
-		performCommit("", "", lastMsg, true)
+		if !guardRewrite("Amending", git.RevParse("HEAD")) {
+			fmt.Println("Aborted.")
+			return
+		}
+		lease := git.RevParse("@{upstream}")
+		if performCommit(undo.Begin("amend"), "", "", lastMsg, true) {
+			pushRewritten(lease)
+		}
 	},
 }
 
 func init() {
+	addRewriteFlags(amendCmd)
+	addCheckFlags(amendCmd)
 	rootCmd.AddCommand(amendCmd)
 }
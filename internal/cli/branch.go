@@ -0,0 +1,53 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+
+	"raven/internal/analysis"
+	"raven/internal/config"
+	"raven/internal/git"
+	"raven/internal/ui"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/spf13/cobra"
+)
+
+var (
+	branchBaseFlag     string
+	branchTemplateFlag string
+)
+
+var branchCmd = &cobra.Command{
+	Use:     "branch",
+	Aliases: []string{"br"},
+	Short:   "Manage branches interactively",
+	Long:    "Lists local and remote branches with ahead/behind counts, last commit age and merged status. Checkout, create, rename, set upstream and clean up merged branches from one screen.",
+	Run: func(cmd *cobra.Command, args []string) {
+		if !git.IsRepository() {
+			fmt.Println("Error: This is not a git repository.")
+			os.Exit(1)
+		}
+
+		base := branchBaseFlag
+		if base == "" {
+			base = config.Get("branch.base", git.DefaultBranch())
+		}
+		template := branchTemplateFlag
+		if template == "" {
+			template = config.Get("branch.template", analysis.DefaultBranchTemplate)
+		}
+
+		p := tea.NewProgram(ui.InitialBranchModel(base, template), tea.WithAltScreen())
+		if _, err := p.Run(); err != nil {
+			fmt.Println("Error running UI:", err)
+			os.Exit(1)
+		}
+	},
+}
+
+func init() {
+	branchCmd.Flags().StringVar(&branchBaseFlag, "base", "", "Branch to check merged status against (default raven.branch.base, origin/HEAD, main or master)")
+	branchCmd.Flags().StringVar(&branchTemplateFlag, "template", "", "Name template for new branches (default raven.branch.template or "+analysis.DefaultBranchTemplate+")")
+	rootCmd.AddCommand(branchCmd)
+}
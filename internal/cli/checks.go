@@ -0,0 +1,217 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"raven/internal/checks"
+	"raven/internal/config"
+	"raven/internal/git"
+	"raven/internal/ui"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/spf13/cobra"
+)
+
+// noVerifyFlag skips the pre-commit checks of commit, save and amend,
+// allowLargeFlag their large-file guard.
+var (
+	noVerifyFlag   bool
+	allowLargeFlag bool
+)
+
+// addCheckFlags adds --no-verify and --allow-large to a committing command.
+func addCheckFlags(cmd *cobra.Command) {
+	cmd.Flags().BoolVarP(&noVerifyFlag, "no-verify", "n", false, "Skip the pre-commit checks (not the large-file guard, see --allow-large)")
+	cmd.Flags().BoolVar(&allowLargeFlag, "allow-large", false, "Commit large files without asking")
+}
+
+// configuredChecks builds the pre-commit checks: the built-in ones listed in
+// raven.checks (comma separated, "none" for none, default checks.Defaults)
+// followed by the raven.check.command commands. The checks named in
+// raven.check.warn only warn.
+func configuredChecks() []checks.Check {
+	names := checks.Defaults
+	if list := config.Get("checks", ""); list != "" {
+		names = nil
+		for _, name := range strings.Split(list, ",") {
+			if name = strings.TrimSpace(name); name != "" && name != "none" {
+				names = append(names, name)
+			}
+		}
+	}
+
+	opts := checkOptions()
+	opts.Allowlist = secretsAllowlist()
+
+	warn := make(map[string]bool)
+	for _, name := range strings.Split(config.Get("check.warn", ""), ",") {
+		warn[strings.TrimSpace(name)] = true
+	}
+
+	var list []checks.Check
+	for _, name := range names {
+		c, ok := checks.Builtin(name, opts)
+		if !ok {
+			fmt.Printf("Warning: unknown check %q in raven.checks (known: %s).\n", name, strings.Join(checks.Names, ", "))
+			continue
+		}
+		c.Warn = warn[name]
+		list = append(list, c)
+	}
+	for _, command := range config.GetAll("check.command") {
+		c := checks.Command(command)
+		c.Warn = warn[c.Name]
+		list = append(list, c)
+	}
+	return list
+}
+
+// checkOptions reads the size limits raven.check.maxSize and
+// raven.check.maxBinarySize (e.g. "10MB").
+func checkOptions() checks.Options {
+	var opts checks.Options
+	for key, limit := range map[string]*int64{"check.maxSize": &opts.MaxSize, "check.maxBinarySize": &opts.MaxBinarySize} {
+		if size := config.Get(key, ""); size != "" {
+			n, err := checks.ParseSize(size)
+			if err != nil {
+				fmt.Printf("Warning: ignoring raven.%s: %v\n", key, err)
+				continue
+			}
+			*limit = n
+		}
+	}
+	return opts
+}
+
+// secretsAllowlist reads the allowlist of the secrets check from
+// raven.secrets.allowlist, by default .secrets-allowlist at the repository root.
+func secretsAllowlist() checks.Allowlist {
+	file := config.Get("secrets.allowlist", "")
+	if file == "" {
+		root, err := git.TopLevel()
+		if err != nil {
+			return checks.Allowlist{}
+		}
+		file = filepath.Join(root, checks.DefaultAllowlistFile)
+	}
+	allow, err := checks.LoadAllowlist(file)
+	if err != nil {
+		fmt.Println("Warning: could not read the secrets allowlist:", err)
+	}
+	return allow
+}
+
+// runPreCommitChecks runs the configured checks on the staged changes with a
+// progress view. It returns the problems blocking the commit and the
+// warnings, one per line, and false if the user interrupted it. Nothing runs
+// with --no-verify or when nothing is staged.
+func runPreCommitChecks() (failures, warnings []string, ok bool) {
+	if noVerifyFlag {
+		return nil, nil, true
+	}
+	list := configuredChecks()
+	if len(list) == 0 {
+		return nil, nil, true
+	}
+	staged, err := checks.LoadStaged()
+	if err != nil {
+		fmt.Println("Warning: could not read the staged changes, skipping the checks:", err)
+		return nil, nil, true
+	}
+	if len(staged.Files) == 0 && len(staged.Diff) == 0 {
+		return nil, nil, true
+	}
+
+	if !isTerminal(os.Stdout) { // Scripts and hooks: no progress view
+		for _, r := range checks.RunAll(list, staged) {
+			switch {
+			case r.Blocking():
+				failures = append(failures, r.Lines()...)
+			case r.Failed():
+				warnings = append(warnings, r.Lines()...)
+			}
+		}
+		return failures, warnings, true
+	}
+
+	m, err := tea.NewProgram(ui.InitialChecksModel(list, staged)).Run()
+	if err != nil {
+		fmt.Println("Error running UI:", err)
+		os.Exit(1)
+	}
+	result := m.(ui.ChecksModel)
+	if result.Canceled {
+		return nil, nil, false
+	}
+	return result.Failures(), result.Warnings(), true
+}
+
+// isTerminal reports whether f is a terminal rather than a pipe or file.
+func isTerminal(f *os.File) bool {
+	info, err := f.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
+
+// guardLargeFiles warns about staged files over their size limit and lets
+// the user unstage them, ignore them, track them with git LFS or commit them
+// anyway. It reports whether to go on with the commit and whether the index
+// changed. Nothing is checked with --allow-large; --no-verify does not skip it.
+func guardLargeFiles() (proceed, changed bool) {
+	if allowLargeFlag {
+		return true, false
+	}
+	staged, err := checks.LoadStaged()
+	if err != nil {
+		return true, false
+	}
+	large := checks.LargeFiles(staged, checkOptions())
+	if len(large) == 0 {
+		return true, false
+	}
+
+	if !isTerminal(os.Stdout) {
+		var lines []string
+		for _, f := range large {
+			lines = append(lines, f.Path+": "+f.Reason())
+		}
+		printProblems("Large files staged", lines)
+		fmt.Println("Unstage them (git restore --staged <file>), track them with git LFS, or use --allow-large.")
+		os.Exit(1)
+	}
+
+	m, err := tea.NewProgram(ui.InitialLargeFileModel(large, git.LFSAvailable())).Run()
+	if err != nil {
+		fmt.Println("Error running UI:", err)
+		os.Exit(1)
+	}
+	result := m.(ui.LargeFileModel)
+	if !result.Confirmed {
+		return false, false
+	}
+
+	for _, it := range result.Items {
+		var err error
+		switch it.Action {
+		case ui.LargeFileUnstage:
+			err = git.Unstage(it.File.Path)
+		case ui.LargeFileIgnore:
+			if err = git.Unstage(it.File.Path); err == nil {
+				err = git.Ignore("/" + it.File.Path)
+			}
+		case ui.LargeFileLFS:
+			err = git.LFSTrack(checks.LFSPattern(it.File.Path), it.File.Path)
+		default:
+			continue
+		}
+		if err != nil {
+			fmt.Printf("Error with %s: %v\n", it.File.Path, err)
+			os.Exit(1)
+		}
+		changed = true
+		fmt.Printf("%s: %s\n", it.File.Path, ui.DescribeLargeFileAction(it))
+	}
+	return true, changed
+}
@@ -3,13 +3,10 @@ package cli
 import (
 	"fmt"
 	"os"
-	"os/exec"
 
-	"raven/internal/analysis"
 	"raven/internal/git"
-	"raven/internal/ui"
+	"raven/internal/undo"
 
-	tea "github.com/charmbracelet/bubbletea"
 	"github.com/spf13/cobra"
 )
 
@@ -23,7 +20,8 @@ var commitCmd = &cobra.Command{
 			os.Exit(1)
 		}
 
-		// 1. Check for staged changes
+		// 1. Check for staged changes (undo restores the index from before staging)
+		op := undo.Begin("commit")
 		diff, err := git.GetStagedDiff()
 		if err != nil {
 			fmt.Printf("Error getting staged changes: %v\n", err)
@@ -52,47 +50,8 @@ var commitCmd = &cobra.Command{
 			}
 		}
 
-		// 3. Proceed with Analysis & Commit
-		var finalMsg string
-
-		if commitMsgFlag != "" {
-			// MANUAL MODE: Use the provided flag
-			finalMsg = commitMsgFlag
-		} else {
-			// AI MODE: Analyze and Suggest
-			suggestion := analysis.AnalyzeDiff(diff)
-			msg := ""
-			if suggestion.Scope != "" {
-				msg = fmt.Sprintf("%s(%s): %s", suggestion.Type, suggestion.Scope, suggestion.Description)
-			} else {
-				msg = fmt.Sprintf("%s: %s", suggestion.Type, suggestion.Description)
-			}
-
-			// Interactive UI
-			p := tea.NewProgram(ui.InitialModel(msg))
-			m, err := p.Run()
-			if err != nil {
-				fmt.Println("Error running UI:", err)
-				os.Exit(1)
-			}
-
-			finalModel := m.(ui.Model)
-			if finalModel.Choice == ui.ChoiceCancel {
-				fmt.Println("Commit canceled.")
-				return
-			}
-			finalMsg = finalModel.Message
-		}
-
-		// Execute Commit
-		c := exec.Command("git", "commit", "-m", finalMsg)
-		c.Stdout = os.Stdout
-		c.Stderr = os.Stderr
-		if err := c.Run(); err != nil {
-			fmt.Println("Error committing:", err)
-		} else {
-			fmt.Println("Commit successful! 🚀")
-		}
+		// 3. Checks, Analysis & Commit
+		performCommit(op, diff, commitMsgFlag, "", false)
 	},
 }
 
@@ -102,5 +61,6 @@ var (
 
 func init() {
 	commitCmd.Flags().StringVarP(&commitMsgFlag, "message", "m", "", "Commit message")
+	addCheckFlags(commitCmd)
 	rootCmd.AddCommand(commitCmd)
 }
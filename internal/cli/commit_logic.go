@@ -3,20 +3,26 @@ package cli
 import (
 	"fmt"
 	"os"
-	"os/exec"
+	"regexp"
 
 	"raven/internal/analysis"
+	"raven/internal/config"
+	"raven/internal/git"
 	"raven/internal/ui"
+	"raven/internal/undo"
 
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
 )
 
-// performCommit handles the analysis, TUI, and final execution of a commit.
-// If manualMessage is provided, it skips analysis/TUI and commits directly.
 // performCommit handles the analysis, TUI, and final execution of a commit.
 // If manualMessage is provided, it skips analysis/TUI and commits directly.
 // If amend is true, it uses `git commit --amend`.
-func performCommit(diff string, manualMessage string, overrideMsg string, amend bool) {
+// The large-file guard and the pre-commit checks run first; check failures
+// block the commit.
+// op was begun by the caller (before any staging) and is recorded for undo.
+// It reports whether the commit was made.
+func performCommit(op *undo.Operation, diff string, manualMessage string, overrideMsg string, amend bool) bool {
 	var finalMsg string
 
 	if manualMessage != "" {
@@ -67,23 +73,56 @@ func performCommit(diff string, manualMessage string, overrideMsg string, amend
 
 	// func performCommit(diff string, manualMessage string, overrideMsg string, amend bool)
 
+	// Large-file guard, before the analysis sees the diff (skipped with --allow-large)
+	proceed, changed := guardLargeFiles()
+	if !proceed {
+		fmt.Println("Commit canceled.")
+		return false
+	}
+	if changed {
+		diff, _ = git.GetStagedDiff()
+		if diff == "" && !amend {
+			fmt.Println("Nothing left to commit.")
+			return false
+		}
+	}
+
 	msg := overrideMsg
+	var branchIssue string
 	if msg == "" && manualMessage == "" {
-		// AI MODE: Analyze
-		suggestion := analysis.AnalyzeDiff(diff)
-		if suggestion.Scope != "" {
-			msg = fmt.Sprintf("%s(%s): %s", suggestion.Type, suggestion.Scope, suggestion.Description)
-		} else {
-			msg = fmt.Sprintf("%s: %s", suggestion.Type, suggestion.Description)
-		}
+		// AI MODE: Analyze (seeded by the branch name)
+		suggestion := suggestCommit(diff)
+		msg = suggestion.Header()
+		branchIssue = suggestion.Issue
 	} else if manualMessage != "" {
 		msg = manualMessage
 	}
 
+	// Pre-commit checks on the staged files (skipped with --no-verify)
+	failures, warnings, ok := runPreCommitChecks()
+	if !ok {
+		fmt.Println("Commit canceled.")
+		return false
+	}
+	if manualMessage != "" && len(warnings) > 0 {
+		fmt.Println(lipgloss.NewStyle().Foreground(lipgloss.Color("#F59E0B")).Bold(true).Render("⚠ Pre-commit warnings"))
+		for _, w := range warnings {
+			fmt.Println("    " + w)
+		}
+	}
+	if manualMessage != "" && len(failures) > 0 {
+		printProblems("Pre-commit checks failed", failures)
+		fmt.Println("Fix them and commit again, or use --no-verify to skip the checks.")
+		os.Exit(1)
+	}
+
 	// Interactive UI (skips if manualMessage was set, wait logic below...)
 	if manualMessage == "" {
 		// Interactive UI
-		p := tea.NewProgram(ui.InitialModel(msg))
+		model := commitModel(msg, branchIssue)
+		model.CheckFailures = failures
+		model.CheckWarnings = warnings
+		p := tea.NewProgram(model)
 		m, err := p.Run()
 		if err != nil {
 			fmt.Println("Error running UI:", err)
@@ -93,9 +132,9 @@ func performCommit(diff string, manualMessage string, overrideMsg string, amend
 		finalModel := m.(ui.Model)
 		if finalModel.Choice == ui.ChoiceCancel {
 			fmt.Println("Commit canceled.")
-			return
+			return false
 		}
-		finalMsg = finalModel.Message
+		finalMsg = finalModel.FullMessage()
 	} else {
 		finalMsg = msg
 	}
@@ -106,16 +145,100 @@ func performCommit(diff string, manualMessage string, overrideMsg string, amend
 		args = append(args, "--amend")
 	}
 
-	c := exec.Command("git", args...)
+	c := op.Git(args...)
 	c.Stdout = os.Stdout
 	c.Stderr = os.Stderr
 	if err := c.Run(); err != nil {
 		fmt.Println("Error committing:", err)
+		return false
+	}
+	recordOperation(op)
+	if amend {
+		fmt.Println("Commit amended successfully! 🚀")
 	} else {
-		if amend {
-			fmt.Println("Commit amended successfully! 🚀")
-		} else {
-			fmt.Println("Commit successful! 🚀")
+		fmt.Println("Commit successful! 🚀")
+	}
+	return true
+}
+
+// suggestCommit analyzes the staged diff, seeded by the current branch name.
+func suggestCommit(diff string) analysis.Suggestion {
+	return analysis.AnalyzeDiffOnBranch(diff, currentBranchContext())
+}
+
+// currentBranchContext parses the checked out branch with the raven.branchPattern
+// patterns and the raven.branch.template, tried before the built-in ones.
+func currentBranchContext() analysis.BranchContext {
+	status, err := git.GetStatus()
+	if err != nil {
+		return analysis.BranchContext{}
+	}
+	custom := config.GetAll("branchPattern")
+	if template := config.Get("branch.template", ""); template != "" {
+		custom = append(custom, analysis.TemplatePattern(template))
+	}
+	patterns, err := analysis.CompileBranchPatterns(append(custom, analysis.DefaultBranchPatterns...))
+	if err != nil {
+		fmt.Println("Warning: ignoring invalid raven.branchPattern:", err)
+		patterns, _ = analysis.CompileBranchPatterns(analysis.DefaultBranchPatterns)
+	}
+	ctx, _ := analysis.ParseBranch(status.Branch(), patterns)
+	return ctx
+}
+
+// commitModel builds the commit TUI for a message, offering the branch issue
+// (preselected) and the issues of recent commits as reference trailers.
+func commitModel(msg, branchIssue string) ui.Model {
+	model := ui.InitialModel(msg)
+	model.TrailerKeys = trailerKeys()
+	model.Issues = issueCandidates(branchIssue, model.TrailerKeys[0])
+	return model
+}
+
+// trailerKeys returns the raven.trailer keys issues can be referenced with,
+// the first being the default.
+func trailerKeys() []string {
+	if keys := config.GetAll("trailer"); len(keys) > 0 {
+		return keys
+	}
+	return analysis.DefaultTrailerKeys
+}
+
+// issuePattern compiles raven.issuePattern, falling back to the default.
+func issuePattern() *regexp.Regexp {
+	pattern := config.Get("issuePattern", analysis.DefaultIssuePattern)
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		fmt.Println("Warning: ignoring invalid raven.issuePattern:", err)
+		re = regexp.MustCompile(analysis.DefaultIssuePattern)
+	}
+	return re
+}
+
+// recentIssueCommits is how many commits are searched for issue keys.
+const recentIssueCommits = 20
+
+// issueCandidates lists the branch issue followed by the issues referenced in
+// recent commits, all using the given trailer.
+func issueCandidates(branchIssue, trailer string) []ui.IssueRef {
+	var issues []ui.IssueRef
+	seen := make(map[string]bool)
+	if branchIssue != "" {
+		issues = append(issues, ui.IssueRef{Key: branchIssue, Trailer: trailer, Source: "branch", Selected: true})
+		seen[branchIssue] = true
+	}
+	messages, err := git.GetMessages("-n", fmt.Sprint(recentIssueCommits))
+	if err != nil {
+		return issues // No commits yet
+	}
+	re := issuePattern()
+	for _, m := range messages {
+		for _, key := range analysis.ExtractIssues(m.Text, re) {
+			if !seen[key] {
+				seen[key] = true
+				issues = append(issues, ui.IssueRef{Key: key, Trailer: trailer, Source: "recent commit"})
+			}
 		}
 	}
+	return issues
 }
@@ -3,9 +3,9 @@ package cli
 import (
 	"fmt"
 	"os"
-	"os/exec"
 
 	"raven/internal/git"
+	"raven/internal/undo"
 
 	"github.com/charmbracelet/lipgloss"
 	"github.com/spf13/cobra"
@@ -15,14 +15,27 @@ var fixCmd = &cobra.Command{
 	Use:     "fix",
 	Aliases: []string{"f"},
 	Short:   "Quickly fix the last commit (stage all & amend silent)",
-	Long:    "Stages all tracked/untracked changes and folds them into the last commit without changing the message.",
+	Long:    "Stages all tracked/untracked changes and folds them into the last commit without changing the message.\n\nWith --pick, folds the staged changes (or all changes if nothing is staged) into an earlier unpushed commit instead: pick it from a list ranked by how much it overlaps with them, and raven creates a fixup! commit for it. --autosquash then squashes it in with a non-interactive rebase.",
 	Run: func(cmd *cobra.Command, args []string) {
 		if !git.IsRepository() {
 			fmt.Println("Error: This is not a git repository.")
 			os.Exit(1)
 		}
 
-		// 1. Stage All
+		if fixPickFlag {
+			runFixPick()
+			return
+		}
+
+		// 0. Refuse to silently rewrite a pushed commit
+		if !guardRewrite("Fixing", git.RevParse("HEAD")) {
+			fmt.Println("Aborted.")
+			return
+		}
+		lease := git.RevParse("@{upstream}")
+
+		// 1. Stage All (undo restores the index from before this)
+		op := undo.Begin("fix")
 		if err := git.StageFile("."); err != nil {
 			fmt.Println("Error staging files:", err)
 			os.Exit(1)
@@ -40,22 +53,32 @@ var fixCmd = &cobra.Command{
 		}
 
 		// 2. Commit Amend No-Edit
-		c := exec.Command("git", "commit", "--amend", "--no-edit")
+		c := op.Git("commit", "--amend", "--no-edit")
 		c.Stdout = os.Stdout
 		c.Stderr = os.Stderr
 		if err := c.Run(); err != nil {
 			fmt.Println("Error fixing commit:", err)
 			os.Exit(1)
 		}
+		recordOperation(op)
 
 		// Success Message
 		fmt.Println(lipgloss.NewStyle().
 			Foreground(lipgloss.Color("#38BDF8")).
 			Bold(true).
 			Render("✔ Patched last commit successfully."))
+		pushRewritten(lease)
 	},
 }
 
+var (
+	fixPickFlag       bool
+	fixAutosquashFlag bool
+)
+
 func init() {
+	fixCmd.Flags().BoolVarP(&fixPickFlag, "pick", "p", false, "Create a fixup! commit for an earlier unpushed commit")
+	fixCmd.Flags().BoolVar(&fixAutosquashFlag, "autosquash", false, "With --pick, squash the fixup in right away (or set raven.fix.autosquash)")
+	addRewriteFlags(fixCmd)
 	rootCmd.AddCommand(fixCmd)
 }
@@ -0,0 +1,150 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"raven/internal/analysis"
+	"raven/internal/config"
+	"raven/internal/git"
+	"raven/internal/ui"
+	"raven/internal/undo"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// fixupCandidates is how many unpushed commits are offered as targets.
+const fixupCandidates = 30
+
+// runFixPick creates a fixup! commit of the staged changes (all changes if
+// nothing is staged) for an unpushed commit picked from a ranked list.
+// Changes staged for it are unstaged again if no commit is made.
+func runFixPick() {
+	op := undo.Begin("fixup")
+
+	diff, err := git.GetStagedDiff()
+	if err != nil {
+		fmt.Printf("Error getting staged changes: %v\n", err)
+		os.Exit(1)
+	}
+	restore := func() {}
+	if diff == "" {
+		index, err := git.WriteTree()
+		if err != nil {
+			fmt.Println("Error saving the index:", err)
+			os.Exit(1)
+		}
+		restore = func() {
+			if err := git.ReadTree(index); err != nil {
+				fmt.Println("Error restoring the index:", err)
+			}
+		}
+		if err := git.StageFile("."); err != nil {
+			restore()
+			fmt.Println("Error staging files:", err)
+			os.Exit(1)
+		}
+		diff, _ = git.GetStagedDiff()
+	}
+	if diff == "" {
+		fmt.Println("Nothing to fix (working tree clean).")
+		return
+	}
+
+	// Commits not on any remote can be rewritten safely.
+	messages, err := git.GetMessages("-n", fmt.Sprint(fixupCandidates), "HEAD", "--not", "--remotes")
+	if err != nil {
+		restore()
+		fmt.Println("Error reading commits:", err)
+		os.Exit(1)
+	}
+	var commits []git.Message
+	for _, m := range messages {
+		if !analysis.IsAutosquash(m.Text) {
+			commits = append(commits, m)
+		}
+	}
+	if len(commits) == 0 {
+		restore()
+		fmt.Println("No unpushed commits to fix up. Use 'raven fix' to amend the last commit.")
+		return
+	}
+
+	staged := analysis.ParseDiff(diff)
+	scores := make([]analysis.FixupScore, len(commits))
+	for i, c := range commits {
+		patch, err := git.CommitPatch(c.Hash)
+		if err == nil {
+			scores[i] = analysis.ScoreFixup(staged, analysis.ParseDiff(patch))
+		}
+	}
+	var targets []ui.FixupTarget
+	for _, i := range analysis.RankFixups(scores) {
+		targets = append(targets, ui.FixupTarget{Commit: commits[i], Score: scores[i]})
+	}
+
+	m, err := tea.NewProgram(ui.InitialFixupModel(targets)).Run()
+	if err != nil {
+		restore()
+		fmt.Println("Error running UI:", err)
+		os.Exit(1)
+	}
+	chosen := m.(ui.FixupModel).Chosen
+	if chosen < 0 {
+		restore()
+		fmt.Println("Aborted.")
+		return
+	}
+	target := targets[chosen].Commit
+	// Autosquash rewrites history from target on; a declined rewrite still
+	// gets its fixup! commit, to fold in later.
+	squash := fixAutosquashFlag || config.GetBool("fix.autosquash", false)
+	if squash && !guardRewrite("Fixing", target.Hash) {
+		fmt.Println("Not squashing; creating the fixup! commit only.")
+		squash = false
+	}
+
+	c := op.Git("commit", "--quiet", "--fixup="+target.Hash)
+	c.Stdout = os.Stdout
+	c.Stderr = os.Stderr
+	if err := c.Run(); err != nil {
+		restore()
+		fmt.Println("Error creating fixup commit:", err)
+		os.Exit(1)
+	}
+	recordOperation(op)
+	subject, _, _ := strings.Cut(target.Text, "\n")
+	fmt.Println(lipgloss.NewStyle().Foreground(lipgloss.Color("#38BDF8")).Bold(true).
+		Render(fmt.Sprintf("✔ Created fixup! commit for %s %s.", target.ShortHash, subject)))
+
+	if squash {
+		autosquash(target)
+	} else {
+		fmt.Printf("Fold it in later with 'git rebase -i --autosquash %s^', or use --autosquash.\n", target.ShortHash)
+	}
+}
+
+// autosquash folds the fixup! commits into their targets with a
+// non-interactive rebase from target onwards.
+func autosquash(target git.Message) {
+	args := []string{"rebase", "--interactive", "--autosquash", "--autostash"}
+	if git.HasParent(target.Hash) {
+		args = append(args, target.Hash+"^")
+	} else {
+		args = append(args, "--root")
+	}
+
+	op := undo.Begin("autosquash")
+	c := op.Git(args...)
+	c.Env = append(c.Env, "GIT_SEQUENCE_EDITOR=:") // Accept the generated todo list as is
+	c.Stdout = os.Stdout
+	c.Stderr = os.Stderr
+	if err := c.Run(); err != nil {
+		fmt.Println("Autosquash stopped. Resolve the conflicts and run 'git rebase --continue', or 'git rebase --abort' to give up.")
+		os.Exit(1)
+	}
+	recordOperation(op)
+	fmt.Println(lipgloss.NewStyle().Foreground(lipgloss.Color("#38BDF8")).Bold(true).Render("✔ Squashed the fixup into " + target.ShortHash + "'s commit."))
+}
@@ -0,0 +1,138 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"strings"
+
+	"raven/internal/config"
+	"raven/internal/git"
+	"raven/internal/undo"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/spf13/cobra"
+)
+
+// Flags shared by the history-rewriting commands (fix, amend, rebase, undo, redo).
+var (
+	rewriteForceFlag bool
+	rewritePushFlag  bool
+)
+
+// defaultProtectedBranches are used when raven.protectedBranch is unset.
+var defaultProtectedBranches = []string{"main", "master"}
+
+// addRewriteFlags adds --force and --push to a history-rewriting command.
+func addRewriteFlags(cmd *cobra.Command) {
+	cmd.Flags().BoolVar(&rewriteForceFlag, "force", false, "Rewrite even if the commit was pushed or is on a protected branch")
+	cmd.Flags().BoolVar(&rewritePushFlag, "push", false, "Push the rewritten branch with --force-with-lease afterwards (or set raven.pushAfterRewrite)")
+}
+
+// exposure is where a commit is already visible to others.
+type exposure struct {
+	Remote    []string // Remote-tracking branches containing it, e.g. "origin/main"
+	Protected []string // Protected branches (local or remote) containing it
+}
+
+// exposureOf finds the remote and protected branches containing rev.
+// raven.protectedBranch holds glob patterns such as "release/*". They apply
+// to the current branch too: rewriting main while on it is still rewriting main.
+func exposureOf(rev string) exposure {
+	patterns := config.GetAll("protectedBranch")
+	if len(patterns) == 0 {
+		patterns = defaultProtectedBranches
+	}
+	local, remote := git.BranchesContaining(rev)
+
+	e := exposure{Remote: remote}
+	for _, b := range local {
+		if isProtected(patterns, b) {
+			e.Protected = append(e.Protected, b)
+		}
+	}
+	for _, b := range remote {
+		if _, short, ok := strings.Cut(b, "/"); ok && isProtected(patterns, short) {
+			e.Protected = append(e.Protected, b)
+		}
+	}
+	return e
+}
+
+// isProtected reports whether a branch name matches one of the patterns.
+func isProtected(patterns []string, branch string) bool {
+	for _, p := range patterns {
+		if ok, _ := path.Match(p, branch); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// remotesOf returns the remote names of remote-tracking branches, deduplicated.
+func remotesOf(branches []string) []string {
+	seen := make(map[string]bool)
+	var remotes []string
+	for _, b := range branches {
+		remote, _, _ := strings.Cut(b, "/")
+		if !seen[remote] {
+			seen[remote] = true
+			remotes = append(remotes, remote)
+		}
+	}
+	return remotes
+}
+
+// guardRewrite warns before action rewrites rev when rev was already pushed
+// or is on a protected branch, and asks to go on unless --force was given.
+// It reports whether to go on.
+func guardRewrite(action, rev string) bool {
+	if rev == "" {
+		return true
+	}
+	e := exposureOf(rev)
+	if len(e.Remote) == 0 && len(e.Protected) == 0 {
+		return true
+	}
+
+	warn := lipgloss.NewStyle().Foreground(lipgloss.Color("#F472B6")).Bold(true)
+	commit := undo.Short(rev) + " " + git.Subject(rev)
+	if len(e.Remote) > 0 {
+		fmt.Println(warn.Render(fmt.Sprintf("⚠ %s rewrites %s, which is already pushed to %s (remote: %s).",
+			action, commit, strings.Join(e.Remote, ", "), strings.Join(remotesOf(e.Remote), ", "))))
+	}
+	if len(e.Protected) > 0 {
+		fmt.Println(warn.Render(fmt.Sprintf("⚠ %s is on the protected branch %s.", commit, strings.Join(e.Protected, ", "))))
+	}
+	fmt.Println("Anyone who fetched it will have to reconcile their history.")
+
+	if rewriteForceFlag {
+		fmt.Println("Continuing (--force).")
+		return true
+	}
+	fmt.Print("Rewrite it anyway? [y/N]: ")
+	var response string
+	fmt.Scanln(&response)
+	return response == "y" || response == "Y"
+}
+
+// pushRewritten pushes the rewritten branch to its upstream with
+// --force-with-lease when --push or raven.pushAfterRewrite is set. lease is
+// the upstream commit before the rewrite: the push fails if someone else
+// pushed since.
+func pushRewritten(lease string) {
+	if !rewritePushFlag && !config.GetBool("pushAfterRewrite", false) {
+		return
+	}
+	remote, branch := git.UpstreamBranch()
+	if remote == "" {
+		fmt.Println("Not pushing: the branch has no upstream.")
+		return
+	}
+	if err := git.PushForceWithLease(remote, branch, lease); err != nil {
+		fmt.Println("Error pushing:", err)
+		os.Exit(1)
+	}
+	fmt.Println(lipgloss.NewStyle().Foreground(lipgloss.Color("#38BDF8")).Bold(true).
+		Render(fmt.Sprintf("✔ Pushed to %s/%s with --force-with-lease.", remote, branch)))
+}
@@ -45,9 +45,9 @@ func CustomHelpFunc(cmd *cobra.Command, args []string) {
 	fmt.Println(descStyle.Render("  Use 'raven [command] --help' for more info."))
 
 	// 3. Commands Grouping
-	workflowCmds := []string{"status", "add", "commit", "save", "undo", "fix", "amend"}
-	insightCmds := []string{"stats"}
-	systemCmds := []string{"help", "suggest", "completion"}
+	workflowCmds := []string{"status", "add", "commit", "save", "undo", "redo", "fix", "amend", "rebase", "sync", "push", "pr", "stash", "branch"}
+	insightCmds := []string{"log", "stats"}
+	systemCmds := []string{"help", "suggest", "lint", "completion"}
 
 	renderGroup := func(title string, cmdNames []string) {
 		fmt.Println(subHeaderStyle.Render(title))
@@ -0,0 +1,123 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"raven/internal/analysis"
+	"raven/internal/config"
+	"raven/internal/git"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/spf13/cobra"
+)
+
+var lintCmd = &cobra.Command{
+	Use:   "lint [revision-range]",
+	Short: "Check commit messages against the conventions",
+	Long: `Checks that commit messages have a Conventional Commit header and, for the
+types listed in raven.lint.requireIssue (e.g. "feat,fix"), reference an issue
+with one of the raven.trailer trailers (default Refs, Closes).
+
+Without arguments the commits not yet pushed to the upstream are checked
+(or HEAD if there is no upstream). Use --message-file in a commit-msg hook:
+
+  raven lint --message-file "$1"`,
+	Args: cobra.MaximumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		rules := lintRules()
+
+		if lintFileFlag != "" {
+			data, err := os.ReadFile(lintFileFlag)
+			if err != nil {
+				fmt.Println("Error reading message file:", err)
+				os.Exit(1)
+			}
+			problems := analysis.Lint(stripComments(string(data)), rules)
+			if len(problems) > 0 {
+				printProblems("Commit message", problems)
+				os.Exit(1)
+			}
+			return
+		}
+
+		if !git.IsRepository() {
+			fmt.Println("Error: This is not a git repository.")
+			os.Exit(1)
+		}
+
+		revs := []string{"-n", "1", "HEAD"}
+		if len(args) == 1 {
+			revs = []string{args[0]}
+		} else if upstream := git.Upstream(); upstream != "" {
+			revs = []string{upstream + "..HEAD"}
+		}
+		messages, err := git.GetMessages(revs...)
+		if err != nil {
+			fmt.Println("Error reading commits:", err)
+			os.Exit(1)
+		}
+		if len(messages) == 0 {
+			fmt.Println("Nothing to lint.")
+			return
+		}
+
+		failed := 0
+		for _, m := range messages {
+			subject := analysis.FirstLine(m.Text)
+			if problems := analysis.Lint(m.Text, rules); len(problems) > 0 {
+				failed++
+				printProblems(m.ShortHash+" "+subject, problems)
+			}
+		}
+		if failed > 0 {
+			fmt.Printf("\n%d of %d commits have problems.\n", failed, len(messages))
+			os.Exit(1)
+		}
+		fmt.Println(lipgloss.NewStyle().Foreground(lipgloss.Color("#38BDF8")).Bold(true).
+			Render(fmt.Sprintf("✔ %d commits look good.", len(messages))))
+	},
+}
+
+var lintFileFlag string
+
+func init() {
+	lintCmd.Flags().StringVarP(&lintFileFlag, "message-file", "F", "", "Lint the message in this file (commit-msg hook)")
+	rootCmd.AddCommand(lintCmd)
+}
+
+// lintRules reads the lint settings: raven.lint.requireIssue lists the types
+// (comma separated) that must reference an issue.
+func lintRules() analysis.LintRules {
+	var require []string
+	for _, t := range strings.Split(config.Get("lint.requireIssue", ""), ",") {
+		if t = strings.TrimSpace(t); t != "" {
+			require = append(require, t)
+		}
+	}
+	return analysis.LintRules{RequireIssue: require, TrailerKeys: trailerKeys()}
+}
+
+// printProblems prints a commit (or message) followed by its problems.
+func printProblems(title string, problems []string) {
+	fmt.Println(lipgloss.NewStyle().Foreground(lipgloss.Color("#F472B6")).Bold(true).Render("✘ " + title))
+	for _, p := range problems {
+		fmt.Println("    " + p)
+	}
+}
+
+// stripComments removes the "#" comment lines git adds to a message being
+// edited, and everything below the scissors line.
+func stripComments(message string) string {
+	var lines []string
+	for _, line := range strings.Split(message, "\n") {
+		if strings.HasPrefix(line, "# ------------------------ >8 ------------------------") {
+			break
+		}
+		if !strings.HasPrefix(line, "#") {
+			lines = append(lines, line)
+		}
+	}
+	return strings.TrimSpace(strings.Join(lines, "\n"))
+}
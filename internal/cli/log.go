@@ -0,0 +1,55 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+
+	"raven/internal/git"
+	"raven/internal/ui"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/spf13/cobra"
+)
+
+var (
+	logTypeFlag   string
+	logScopeFlag  string
+	logAuthorFlag string
+	logSinceFlag  string
+	logUntilFlag  string
+)
+
+var logCmd = &cobra.Command{
+	Use:     "log [-- paths...]",
+	Aliases: []string{"l"},
+	Short:   "Browse commit history interactively",
+	Long:    "Lists commits with graph lines and Conventional Commit badges. The detail pane shows the full message and diff of the highlighted commit.",
+	Run: func(cmd *cobra.Command, args []string) {
+		if !git.IsRepository() {
+			fmt.Println("Error: This is not a git repository.")
+			os.Exit(1)
+		}
+
+		opts := git.LogOptions{
+			Author: logAuthorFlag,
+			Since:  logSinceFlag,
+			Until:  logUntilFlag,
+			Paths:  args,
+		}
+
+		p := tea.NewProgram(ui.InitialLogModel(opts, logTypeFlag, logScopeFlag), tea.WithAltScreen())
+		if _, err := p.Run(); err != nil {
+			fmt.Println("Error running UI:", err)
+			os.Exit(1)
+		}
+	},
+}
+
+func init() {
+	logCmd.Flags().StringVarP(&logTypeFlag, "type", "t", "", "Only show commits of this type (feat, fix, ...)")
+	logCmd.Flags().StringVarP(&logScopeFlag, "scope", "s", "", "Only show commits with this scope")
+	logCmd.Flags().StringVarP(&logAuthorFlag, "author", "a", "", "Only show commits by matching author")
+	logCmd.Flags().StringVar(&logSinceFlag, "since", "", "Only show commits after this date (e.g. 2024-01-01, '2 weeks ago')")
+	logCmd.Flags().StringVar(&logUntilFlag, "until", "", "Only show commits before this date")
+	rootCmd.AddCommand(logCmd)
+}
@@ -0,0 +1,145 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"raven/internal/analysis"
+	"raven/internal/config"
+	"raven/internal/git"
+
+	"github.com/atotto/clipboard"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/spf13/cobra"
+)
+
+var (
+	prCopyFlag     bool
+	prTemplateFlag string
+)
+
+// prTemplates are the usual places of a repository's pull request template.
+var prTemplates = []string{
+	".github/pull_request_template.md",
+	".github/PULL_REQUEST_TEMPLATE.md",
+	"PULL_REQUEST_TEMPLATE.md",
+	"docs/pull_request_template.md",
+}
+
+var prCmd = &cobra.Command{
+	Use:   "pr",
+	Short: "Pull request helpers",
+}
+
+var prDescribeCmd = &cobra.Command{
+	Use:   "describe [base]",
+	Short: "Write a pull request title and description from the branch commits",
+	Long: `Gathers the commits and diff stats between base and HEAD and renders a
+Markdown title and body: changes grouped by type and scope, breaking changes
+and referenced issues. Commits closing an issue (Closes:, Fixes:, Resolves:)
+are listed as "Closes ABC-42" so the tracker closes it on merge.
+
+The base defaults to raven.pr.base, else the default branch (its remote copy
+when there is one). The repository's pull request template is used when
+found: {{title}}, {{summary}}, {{changes}}, {{breaking}}, {{issues}} and
+{{body}} are filled in; a template without placeholders is appended.`,
+	Args: cobra.MaximumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		if !git.IsRepository() {
+			fmt.Println("Error: This is not a git repository.")
+			os.Exit(1)
+		}
+
+		base := prBase(args)
+		if base == "" || git.RevParse(base) == "" {
+			fmt.Println("Error: No base branch found. Pass one: raven pr describe main")
+			os.Exit(1)
+		}
+		messages, err := git.GetMessages("--reverse", base+"..HEAD")
+		if err != nil {
+			fmt.Println("Error reading commits:", err)
+			os.Exit(1)
+		}
+		if len(messages) == 0 {
+			fmt.Printf("No commits between %s and HEAD.\n", base)
+			return
+		}
+		var stat analysis.DiffStat
+		stat.Files, stat.Added, stat.Deleted, err = git.DiffStat(base + "...HEAD")
+		if err != nil {
+			fmt.Println("Error reading the diff:", err)
+			os.Exit(1)
+		}
+
+		commits := make([]analysis.PRCommit, len(messages))
+		for i, m := range messages {
+			commits[i] = analysis.PRCommit{ShortHash: m.ShortHash, Message: m.Text}
+		}
+		pr := analysis.DescribePR(commits, stat, issuePattern())
+		text := "# " + pr.Title + "\n\n" + pr.Render(prTemplate()) + "\n"
+
+		if prCopyFlag {
+			if err := clipboard.WriteAll(text); err != nil {
+				fmt.Println("Warning: could not copy to the clipboard:", err)
+				fmt.Print(text)
+				return
+			}
+			fmt.Println(lipgloss.NewStyle().Foreground(lipgloss.Color("#38BDF8")).Bold(true).
+				Render(fmt.Sprintf("✔ Copied the description of %q to the clipboard.", pr.Title)))
+			return
+		}
+		fmt.Print(text)
+	},
+}
+
+// prBase returns the base to compare against: the argument, raven.pr.base,
+// or the default branch, preferring its remote copy.
+func prBase(args []string) string {
+	if len(args) == 1 {
+		return args[0]
+	}
+	if base := config.Get("pr.base", ""); base != "" {
+		return base
+	}
+	base := git.DefaultBranch()
+	if base != "" && git.RevParse("origin/"+base) != "" {
+		return "origin/" + base
+	}
+	return base
+}
+
+// prTemplate reads the pull request template from --template, raven.pr.template
+// or the usual locations in the repository. It returns "" if there is none.
+func prTemplate() string {
+	path := prTemplateFlag
+	if path == "" {
+		path = config.Get("pr.template", "")
+	}
+	if path != "" {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "Warning: could not read the template:", err)
+			return ""
+		}
+		return string(data)
+	}
+
+	root, err := git.TopLevel()
+	if err != nil {
+		return ""
+	}
+	for _, name := range prTemplates {
+		if data, err := os.ReadFile(filepath.Join(root, name)); err == nil {
+			return string(data)
+		}
+	}
+	return ""
+}
+
+func init() {
+	prDescribeCmd.Flags().BoolVarP(&prCopyFlag, "copy", "c", false, "Copy the description to the clipboard instead of printing it")
+	prDescribeCmd.Flags().StringVarP(&prTemplateFlag, "template", "t", "", "Template file (default: raven.pr.template or the repository's pull request template)")
+	prCmd.AddCommand(prDescribeCmd)
+	rootCmd.AddCommand(prCmd)
+}
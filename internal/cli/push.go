@@ -0,0 +1,178 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"raven/internal/analysis"
+	"raven/internal/config"
+	"raven/internal/git"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/spf13/cobra"
+)
+
+var (
+	pushNoVerifyFlag bool
+	pushYesFlag      bool
+)
+
+var pushCmd = &cobra.Command{
+	Use:     "push [remote]",
+	Aliases: []string{"p"},
+	Short:   "Push the current branch after checking its commits",
+	Long: `Pushes the current branch to its upstream. On the first push the branch is
+published on remote (default: raven.push.remote, else origin) under the same
+name and becomes its upstream.
+
+Commits whose messages fail 'raven lint' are refused (skip with --no-verify),
+and WIP or fixup!/squash! commits ask for confirmation first.`,
+	Args: cobra.MaximumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		if !git.IsRepository() {
+			fmt.Println("Error: This is not a git repository.")
+			os.Exit(1)
+		}
+		current := git.CurrentBranch()
+		if current == "" {
+			fmt.Println("Error: HEAD is detached; check out a branch to push.")
+			os.Exit(1)
+		}
+
+		remote, branch := git.UpstreamBranch()
+		firstPush := remote == ""
+		if firstPush || len(args) == 1 {
+			remote = config.Get("push.remote", "origin")
+			if len(args) == 1 {
+				remote = args[0]
+			}
+			branch = current
+		}
+		if git.GetConfig("remote."+remote+".url") == "" {
+			fmt.Printf("Error: There is no remote %q. Add one with 'git remote add %s <url>'.\n", remote, remote)
+			os.Exit(1)
+		}
+		target := remote + "/" + branch
+
+		// The commits the remote does not have yet.
+		revs := []string{target + "..HEAD"}
+		if git.RevParse(target) == "" {
+			revs = []string{"HEAD", "--not", "--remotes=" + remote}
+		}
+		commits, err := git.GetMessages(revs...)
+		if err != nil {
+			fmt.Println("Error reading commits:", err)
+			os.Exit(1)
+		}
+		if len(commits) == 0 && !firstPush {
+			fmt.Println(lipgloss.NewStyle().Foreground(lipgloss.Color("#38BDF8")).Bold(true).
+				Render("✔ Everything is already pushed to " + target + "."))
+			return
+		}
+
+		if !pushNoVerifyFlag && !checkPushMessages(commits) {
+			fmt.Println("Fix them with 'raven rebase -i' (reword), or push anyway with --no-verify.")
+			os.Exit(1)
+		}
+		if !pushYesFlag && !confirmWorkInProgress(commits) {
+			fmt.Println("Aborted.")
+			return
+		}
+
+		if err := git.Push(remote, branch, firstPush); err != nil {
+			fmt.Println("Error pushing:", err)
+			if strings.Contains(err.Error(), "rejected") {
+				fmt.Println("The remote has commits you do not have yet; run 'raven sync'.")
+			}
+			os.Exit(1)
+		}
+
+		done := fmt.Sprintf("✔ Pushed %d commit(s) to %s", len(commits), target)
+		if firstPush {
+			done += " (upstream set)"
+		}
+		fmt.Println(lipgloss.NewStyle().Foreground(lipgloss.Color("#38BDF8")).Bold(true).Render(done + "."))
+		if len(commits) > 0 {
+			listCommits(typeSummary(commits), commits, len(commits))
+		}
+	},
+}
+
+// checkPushMessages lints the commits about to be pushed and reports
+// whether they all pass. Unfinished commits are left to confirmWorkInProgress.
+func checkPushMessages(commits []git.Message) bool {
+	rules := lintRules()
+	failed := 0
+	for _, c := range commits {
+		if analysis.IsWorkInProgress(c.Text) {
+			continue
+		}
+		if problems := analysis.Lint(c.Text, rules); len(problems) > 0 {
+			failed++
+			printProblems(c.ShortHash+" "+analysis.FirstLine(c.Text), problems)
+		}
+	}
+	if failed > 0 {
+		fmt.Printf("\nNot pushing: %d of %d commits fail 'raven lint'.\n", failed, len(commits))
+	}
+	return failed == 0
+}
+
+// confirmWorkInProgress warns about WIP and fixup!/squash! commits and asks
+// whether to push them anyway. It reports whether to go on.
+func confirmWorkInProgress(commits []git.Message) bool {
+	var wip []git.Message
+	for _, c := range commits {
+		if analysis.IsWorkInProgress(c.Text) {
+			wip = append(wip, c)
+		}
+	}
+	if len(wip) == 0 {
+		return true
+	}
+	fmt.Println(lipgloss.NewStyle().Foreground(lipgloss.Color("#F472B6")).Bold(true).
+		Render(fmt.Sprintf("⚠ %d commit(s) look unfinished:", len(wip))))
+	for _, c := range wip {
+		fmt.Printf("    %s %s\n", c.ShortHash, analysis.FirstLine(c.Text))
+	}
+	fmt.Println("Squash or reword them with 'raven rebase -i' before sharing them.")
+	fmt.Print("Push anyway? [y/N]: ")
+	var response string
+	fmt.Scanln(&response)
+	return response == "y" || response == "Y"
+}
+
+// typeSummary counts commits by Conventional type, e.g. "2 feat, 1 fix".
+func typeSummary(commits []git.Message) string {
+	counts := make(map[string]int)
+	for _, c := range commits {
+		h, ok := analysis.ParseHeader(c.Text)
+		if !ok {
+			h.Type = "other"
+		}
+		counts[h.Type]++
+	}
+	types := make([]string, 0, len(counts))
+	for t := range counts {
+		types = append(types, t)
+	}
+	sort.Slice(types, func(i, j int) bool {
+		if counts[types[i]] != counts[types[j]] {
+			return counts[types[i]] > counts[types[j]]
+		}
+		return types[i] < types[j]
+	})
+	parts := make([]string, len(types))
+	for i, t := range types {
+		parts[i] = fmt.Sprintf("%d %s", counts[t], t)
+	}
+	return strings.Join(parts, ", ")
+}
+
+func init() {
+	pushCmd.Flags().BoolVar(&pushNoVerifyFlag, "no-verify", false, "Push even if commit messages fail 'raven lint'")
+	pushCmd.Flags().BoolVarP(&pushYesFlag, "yes", "y", false, "Push WIP and fixup! commits without asking")
+	rootCmd.AddCommand(pushCmd)
+}
@@ -0,0 +1,309 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+
+	"raven/internal/analysis"
+	"raven/internal/git"
+	"raven/internal/ui"
+	"raven/internal/undo"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/spf13/cobra"
+)
+
+var (
+	rebaseInteractiveFlag bool
+	rebaseContinueFlag    bool
+	rebaseSkipFlag        bool
+	rebaseAbortFlag       bool
+	rebaseApplyPlanFlag   string
+)
+
+// rewordDir holds the new messages of reworded commits while a rebase runs,
+// rebasePlan the plan applied to git's todo list.
+const (
+	rewordDir  = "raven/reword"
+	rebasePlan = "raven/rebase-todo"
+)
+
+var rebaseCmd = &cobra.Command{
+	Use:     "rebase [base]",
+	Aliases: []string{"rb"},
+	Short:   "Rebase the current branch, interactively with -i",
+	Long: `Replays the commits of the current branch on top of base (default: its upstream).
+
+With -i, the commits are listed oldest first to reorder (K/J) and to pick,
+reword, edit, squash, fixup or drop. Reworded commits get their new message
+from the raven commit TUI. When the rebase stops for an edit or a conflict,
+raven shows where it is; finish with --continue, --skip or --abort.`,
+	Args: cobra.MaximumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		if !git.IsRepository() {
+			fmt.Println("Error: This is not a git repository.")
+			os.Exit(1)
+		}
+
+		switch {
+		case rebaseApplyPlanFlag != "":
+			applyPlan(rebaseApplyPlanFlag, args)
+			return
+		case rebaseContinueFlag:
+			resumeRebase("--continue")
+			return
+		case rebaseSkipFlag:
+			resumeRebase("--skip")
+			return
+		case rebaseAbortFlag:
+			resumeRebase("--abort")
+			return
+		}
+
+		if git.GetRebaseState().InProgress {
+			fmt.Println("Error: A rebase is already in progress. Use --continue, --skip or --abort.")
+			os.Exit(1)
+		}
+		base := git.Upstream()
+		if len(args) == 1 {
+			base = args[0]
+		}
+		if base == "" {
+			fmt.Println("Error: No base given and the branch has no upstream.")
+			os.Exit(1)
+		}
+		if git.RevParse(base) == "" {
+			fmt.Printf("Error: Unknown revision %q.\n", base)
+			os.Exit(1)
+		}
+
+		commits, err := git.GetMessages("--reverse", base+"..HEAD")
+		if err != nil {
+			fmt.Println("Error reading commits:", err)
+			os.Exit(1)
+		}
+		if len(commits) == 0 && rebaseInteractiveFlag {
+			fmt.Printf("No commits between %s and HEAD.\n", base)
+			return
+		}
+		// The oldest commit is pushed if any of them is.
+		if len(commits) > 0 && !guardRewrite("Rebasing", commits[0].Hash) {
+			fmt.Println("Aborted.")
+			return
+		}
+		lease := git.RevParse("@{upstream}")
+
+		op := undo.Begin("rebase")
+		var c *exec.Cmd
+		if rebaseInteractiveFlag {
+			c = interactiveRebase(op, base, commits)
+			if c == nil {
+				return
+			}
+		} else {
+			c = op.Git("rebase", base)
+		}
+		c.Stdin = os.Stdin
+		c.Stdout = os.Stdout
+		c.Stderr = os.Stderr
+		reportRebase(op, lease, c.Run())
+	},
+}
+
+// interactiveRebase lets the user plan the rebase and returns the git
+// command running it, or nil if the user aborted.
+func interactiveRebase(op *undo.Operation, base string, commits []git.Message) *exec.Cmd {
+	m, err := tea.NewProgram(ui.InitialRebaseModel(base, commits)).Run()
+	if err != nil {
+		fmt.Println("Error running UI:", err)
+		os.Exit(1)
+	}
+	plan := m.(ui.RebaseModel)
+	if !plan.Confirmed {
+		fmt.Println("Rebase aborted.")
+		return nil
+	}
+
+	messageFiles := make(map[string]string)
+	for _, it := range plan.Items {
+		if it.Action == "reword" {
+			if file := rewordMessage(it); file != "" {
+				messageFiles[it.Commit.Hash] = file
+			}
+		}
+	}
+
+	planPath, err := git.GitPath(rebasePlan)
+	if err == nil {
+		err = os.MkdirAll(filepath.Dir(planPath), 0o755)
+	}
+	if err == nil {
+		err = os.WriteFile(planPath, []byte(plan.Todo(messageFiles)), 0o644)
+	}
+	exe, exeErr := os.Executable()
+	if err == nil {
+		err = exeErr
+	}
+	if err != nil {
+		fmt.Println("Error writing the rebase todo list:", err)
+		os.Exit(1)
+	}
+
+	c := op.Git("rebase", "--interactive", base)
+	// git calls the sequence editor with its todo file: apply the plan to it.
+	c.Env = append(c.Env, "GIT_SEQUENCE_EDITOR="+git.ShellQuote(exe)+" rebase --apply-plan "+git.ShellQuote(planPath))
+	return c
+}
+
+// applyPlan rewrites git's todo file (args[0]) with the plan in planPath.
+// It runs as git's sequence editor, see interactiveRebase.
+func applyPlan(planPath string, args []string) {
+	if len(args) != 1 {
+		fmt.Println("Error: --apply-plan needs the todo file.")
+		os.Exit(1)
+	}
+	plan, err := os.ReadFile(planPath)
+	if err != nil {
+		fmt.Println("Error reading the rebase plan:", err)
+		os.Exit(1)
+	}
+	todo, err := os.ReadFile(args[0])
+	if err != nil {
+		fmt.Println("Error reading the rebase todo list:", err)
+		os.Exit(1)
+	}
+	if err := os.WriteFile(args[0], []byte(git.EditTodo(string(todo), string(plan))), 0o644); err != nil {
+		fmt.Println("Error writing the rebase todo list:", err)
+		os.Exit(1)
+	}
+}
+
+// rewordMessage opens the commit TUI for a reworded commit, prefilled by the
+// analyzer and keeping the commit's trailers. It returns the file holding the
+// new message, or "" to keep the old one.
+func rewordMessage(it ui.RebaseItem) string {
+	patch, _ := git.CommitPatch(it.Commit.Hash)
+	suggestion := suggestCommit(patch)
+	model := commitModel(suggestion.Header(), suggestion.Issue)
+	for _, t := range analysis.Trailers(it.Commit.Text) {
+		model.Trailers = append(model.Trailers, t[0]+": "+t[1])
+		for i := range model.Issues {
+			if model.Issues[i].Key == t[1] {
+				model.Issues[i].Selected = false // Already referenced
+			}
+		}
+	}
+
+	fmt.Printf("Reword %s %s\n", it.Commit.ShortHash, it.Subject())
+	m, err := tea.NewProgram(model).Run()
+	if err != nil {
+		fmt.Println("Error running UI:", err)
+		os.Exit(1)
+	}
+	final := m.(ui.Model)
+	if final.Choice == ui.ChoiceCancel {
+		fmt.Printf("Keeping the message of %s.\n", it.Commit.ShortHash)
+		return ""
+	}
+
+	dir, err := git.GitPath(rewordDir)
+	if err == nil {
+		err = os.MkdirAll(dir, 0o755)
+	}
+	path := filepath.Join(dir, it.Commit.Hash)
+	if err == nil {
+		err = os.WriteFile(path, []byte(final.FullMessage()+"\n"), 0o644)
+	}
+	if err != nil {
+		fmt.Println("Error saving the new message:", err)
+		os.Exit(1)
+	}
+	return path
+}
+
+// resumeRebase continues, skips or aborts a stopped rebase.
+func resumeRebase(action string) {
+	if !git.GetRebaseState().InProgress {
+		fmt.Println("No rebase in progress.")
+		return
+	}
+	c := exec.Command("git", "rebase", action)
+	c.Env = append(os.Environ(), "GIT_REFLOG_ACTION=raven rebase")
+	c.Stdin = os.Stdin
+	c.Stdout = os.Stdout
+	c.Stderr = os.Stderr
+	err := c.Run()
+	if action == "--abort" {
+		if err != nil {
+			fmt.Println("Error aborting the rebase:", err)
+			os.Exit(1)
+		}
+		cleanRebase()
+		fmt.Println(lipgloss.NewStyle().Foreground(lipgloss.Color("#38BDF8")).Bold(true).Render("✔ Rebase aborted; the branch is back where it was."))
+		return
+	}
+	reportRebase(nil, "", err)
+}
+
+// reportRebase shows how a rebase run ended: done, stopped for an edit, or
+// stopped on a conflict. op is recorded for undo when the rebase finished in
+// one go; after a stop, 'raven undo' falls back to the branch reflog.
+func reportRebase(op *undo.Operation, lease string, err error) {
+	state := git.GetRebaseState()
+	if !state.InProgress {
+		if err != nil {
+			fmt.Println("Error rebasing:", err)
+			os.Exit(1)
+		}
+		cleanRebase()
+		if op != nil {
+			recordOperation(op)
+		}
+		fmt.Println(lipgloss.NewStyle().Foreground(lipgloss.Color("#38BDF8")).Bold(true).Render("✔ Rebase complete."))
+		if lease != "" {
+			pushRewritten(lease)
+		}
+		return
+	}
+
+	at := ""
+	if state.Stopped != "" {
+		at = " at " + undo.Short(state.Stopped) + " " + git.Subject(state.Stopped)
+	}
+	progress := fmt.Sprintf("(step %d of %d)", state.Step, state.Total)
+	if len(state.Conflicts) > 0 {
+		fmt.Println(lipgloss.NewStyle().Foreground(lipgloss.Color("#F472B6")).Bold(true).Render("✘ Conflict" + at + " " + progress))
+		for _, f := range state.Conflicts {
+			fmt.Println("    " + f)
+		}
+		fmt.Println("Resolve the conflicts, 'git add' the files, then run 'raven rebase --continue'.")
+		fmt.Println("Or 'raven rebase --skip' to drop this commit, 'raven rebase --abort' to go back.")
+		os.Exit(1)
+	}
+	fmt.Println(lipgloss.NewStyle().Foreground(lipgloss.Color("#F59E0B")).Bold(true).Render("⏸ Stopped" + at + " " + progress))
+	fmt.Println("Change what you need (e.g. 'raven amend'), then run 'raven rebase --continue'.")
+}
+
+// cleanRebase removes the files raven kept for a finished rebase.
+func cleanRebase() {
+	if dir, err := git.GitPath(rewordDir); err == nil {
+		os.RemoveAll(dir)
+	}
+	if plan, err := git.GitPath(rebasePlan); err == nil {
+		os.Remove(plan)
+	}
+}
+
+func init() {
+	rebaseCmd.Flags().BoolVarP(&rebaseInteractiveFlag, "interactive", "i", false, "Plan the rebase in an editor: reorder, reword, edit, squash, fixup, drop")
+	rebaseCmd.Flags().BoolVar(&rebaseContinueFlag, "continue", false, "Continue a stopped rebase")
+	rebaseCmd.Flags().BoolVar(&rebaseSkipFlag, "skip", false, "Skip the commit the rebase stopped at")
+	rebaseCmd.Flags().BoolVar(&rebaseAbortFlag, "abort", false, "Abort the rebase and go back to where it started")
+	rebaseCmd.Flags().StringVar(&rebaseApplyPlanFlag, "apply-plan", "", "Apply a plan to git's todo list (used as the sequence editor)")
+	rebaseCmd.Flags().MarkHidden("apply-plan")
+	addRewriteFlags(rebaseCmd)
+	rootCmd.AddCommand(rebaseCmd)
+}
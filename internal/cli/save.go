@@ -5,6 +5,7 @@ import (
 	"os"
 
 	"raven/internal/git"
+	"raven/internal/undo"
 
 	"github.com/spf13/cobra"
 )
@@ -24,7 +25,8 @@ var saveCmd = &cobra.Command{
 			os.Exit(1)
 		}
 
-		// 1. Stage All
+		// 1. Stage All (undo restores the index from before this)
+		op := undo.Begin("save")
 		if err := git.StageFile("."); err != nil {
 			fmt.Println("Error staging files:", err)
 			os.Exit(1)
@@ -43,11 +45,12 @@ var saveCmd = &cobra.Command{
 		}
 
 		// 3. Delegate to Shared Commit Logic
-		performCommit(diff, saveMsgFlag, "", false)
+		performCommit(op, diff, saveMsgFlag, "", false)
 	},
 }
 
 func init() {
 	saveCmd.Flags().StringVarP(&saveMsgFlag, "message", "m", "", "Commit message")
+	addCheckFlags(saveCmd)
 	rootCmd.AddCommand(saveCmd)
 }
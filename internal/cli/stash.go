@@ -0,0 +1,90 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"raven/internal/git"
+	"raven/internal/ui"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/spf13/cobra"
+)
+
+var stashUntrackedFlag bool
+
+var stashCmd = &cobra.Command{
+	Use:     "stash [message]",
+	Aliases: []string{"st"},
+	Short:   "Stash changes with a descriptive message",
+	Long:    "Stashes staged and unstaged changes. Without a message, one is generated from the diff, like 'raven commit' does. Use 'raven stash list' to browse, apply, pop, drop or branch from stashes.",
+	Args:    cobra.ArbitraryArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		if !git.IsRepository() {
+			fmt.Println("Error: This is not a git repository.")
+			os.Exit(1)
+		}
+
+		diff, err := git.GetWorkingDiff()
+		if err != nil {
+			fmt.Println("Error: Cannot stash before the first commit.")
+			os.Exit(1)
+		}
+		status, _ := git.GetStatus()
+		if diff == "" && !(stashUntrackedFlag && hasUntracked(status)) {
+			fmt.Println("Nothing to stash.")
+			return
+		}
+
+		message := strings.Join(args, " ")
+		if message == "" {
+			if diff != "" {
+				message = suggestCommit(diff).Header()
+			} else {
+				message = "chore: stash untracked files"
+			}
+		}
+
+		if err := git.StashPush(message, stashUntrackedFlag); err != nil {
+			fmt.Println("Error stashing changes:", err)
+			os.Exit(1)
+		}
+		fmt.Println(lipgloss.NewStyle().Foreground(lipgloss.Color("#38BDF8")).Bold(true).Render("✔ Stashed: " + message))
+	},
+}
+
+var stashListCmd = &cobra.Command{
+	Use:     "list",
+	Aliases: []string{"ls"},
+	Short:   "Browse stashes interactively",
+	Run: func(cmd *cobra.Command, args []string) {
+		if !git.IsRepository() {
+			fmt.Println("Error: This is not a git repository.")
+			os.Exit(1)
+		}
+
+		p := tea.NewProgram(ui.InitialStashModel(), tea.WithAltScreen())
+		if _, err := p.Run(); err != nil {
+			fmt.Println("Error running UI:", err)
+			os.Exit(1)
+		}
+	},
+}
+
+// hasUntracked reports whether the status lists untracked files.
+func hasUntracked(status git.StatusResult) bool {
+	for _, f := range status.Files {
+		if f.Untracked {
+			return true
+		}
+	}
+	return false
+}
+
+func init() {
+	stashCmd.Flags().BoolVarP(&stashUntrackedFlag, "include-untracked", "u", false, "Also stash untracked files")
+	stashCmd.AddCommand(stashListCmd)
+	rootCmd.AddCommand(stashCmd)
+}
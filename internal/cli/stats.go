@@ -1,9 +1,14 @@
 package cli
 
 import (
+	"encoding/json"
 	"fmt"
 	"os"
+	"runtime"
+	"strings"
+	"time"
 
+	"raven/internal/config"
 	"raven/internal/git"
 	"raven/internal/stats"
 	"raven/internal/ui"
@@ -12,30 +17,279 @@ import (
 	"github.com/spf13/cobra"
 )
 
+var (
+	statsSinceFlag  string
+	statsUntilFlag  string
+	statsMonthFlag  bool
+	statsAuthorFlag string
+	statsMeFlag     bool
+	statsSummary    bool
+	statsJSON       bool
+	statsLanguages  bool
+	statsMetricFlag string
+	statsReposFlag  []string
+	statsWorkspace  bool
+	statsJobsFlag   int
+	statsNoCache    bool
+	statsTimezone   string
+	statsDateFlag   string
+	statsWeekStart  string
+)
+
+// repoScanDepth is how deep --repos directories are searched for repositories.
+const repoScanDepth = 3
+
 var statsCmd = &cobra.Command{
 	Use:   "stats",
 	Short: "Show a heatmap of git contribution history",
+	Long:  "Shows a GitHub-style contribution graph of the last year. Press 'v' to switch to the month view.",
 	Run: func(cmd *cobra.Command, args []string) {
-		if !git.IsRepository() {
-			fmt.Println("Error: This is not a git repository.")
+		data := loadStats()
+
+		// Interactive Calendar Heatmap
+		model := ui.InitialCalendarModel(data.Activity, data.WeekStart)
+		model.SetRange(data.Since, data.Until)
+		model.Bucketing = data.Bucketing
+		if data.AuthorLabel != "" {
+			model.SetAuthorFilter(data.AuthorLabel, data.Filtered)
+		}
+		if statsLanguages {
+			fmt.Println(ui.RenderLanguages(model.Activity, model.RangeStart, model.RangeEnd))
+			return
+		}
+		if statsSummary || statsJSON {
+			summary := model.Activity.Summarize(model.RangeStart, model.RangeEnd)
+			if statsJSON {
+				out, err := json.MarshalIndent(summary, "", "  ")
+				if err != nil {
+					fmt.Println("Error encoding summary:", err)
+					os.Exit(1)
+				}
+				fmt.Println(string(out))
+			} else {
+				fmt.Println(ui.RenderSummary(summary))
+			}
+			return
+		}
+
+		model.SetMetric(data.Metric)
+		if statsMonthFlag {
+			model.Layout = ui.CalendarLayoutMonth
+		}
+
+		p := tea.NewProgram(model)
+		if _, err := p.Run(); err != nil {
+			fmt.Printf("Alas, there's been an error: %v", err)
 			os.Exit(1)
 		}
+	},
+}
+
+// statsData is the history selected by the shared stats flags.
+type statsData struct {
+	Activity    stats.Activity // Unfiltered history
+	Filtered    stats.Activity // History of the --author/--me filter
+	AuthorLabel string         // "" when no author filter is set
+	Since       time.Time      // Zero when unset
+	Until       time.Time      // Defaults to today in the bucketing time zone
+	Metric      stats.Metric
+	Bucketing   stats.Bucketing
+	WeekStart   time.Weekday
+}
+
+// loadStats reads the history according to the shared stats flags.
+// It exits on invalid flags or git errors.
+func loadStats() statsData {
+	roots := statsReposFlag
+	if statsWorkspace {
+		roots = append(roots, config.Workspaces()...)
+		if len(roots) == 0 {
+			fmt.Println("Error: No workspace configured. Add one with: git config --global --add raven.workspace ~/code")
+			os.Exit(1)
+		}
+	}
+	if len(roots) == 0 && !git.IsRepository() {
+		fmt.Println("Error: This is not a git repository.")
+		os.Exit(1)
+	}
+
+	var data statsData
+	var err error
+	data.Since, err = parseDateFlag("since", statsSinceFlag)
+	if err != nil {
+		fmt.Println("Error:", err)
+		os.Exit(1)
+	}
+	data.Until, err = parseDateFlag("until", statsUntilFlag)
+	if err != nil {
+		fmt.Println("Error:", err)
+		os.Exit(1)
+	}
+
+	var ok bool
+	data.Metric, ok = stats.ParseMetric(statsMetricFlag)
+	if !ok {
+		fmt.Printf("Error: unknown --metric %q (use commits, added, deleted or net)\n", statsMetricFlag)
+		os.Exit(1)
+	}
 
-		counts, err := stats.GetCommitCounts()
+	data.Bucketing, data.WeekStart = calendarSettings()
+	if data.Until.IsZero() {
+		data.Until = data.Bucketing.Today()
+	}
+	if !data.Since.IsZero() && data.Since.After(data.Until) {
+		fmt.Printf("Error: --since %s is after --until %s.\n", data.Since.Format("2006-01-02"), data.Until.Format("2006-01-02"))
+		os.Exit(1)
+	}
+
+	// Commits are grouped by day in the chosen time zone and date
+	opts := stats.Options{Bucketing: data.Bucketing, Languages: languageRules()}
+	if len(roots) > 0 {
+		data.Activity = collectRepos(roots, opts)
+	} else {
+		if statsNoCache {
+			data.Activity, err = stats.GetCommitCounts(opts)
+		} else {
+			data.Activity, err = stats.LoadActivity("", opts)
+		}
 		if err != nil {
 			fmt.Printf("Error getting commit history: %v\n", err)
 			os.Exit(1)
 		}
+	}
 
-		// Interactive Calendar Heatmap
-		p := tea.NewProgram(ui.InitialCalendarModel(counts))
-		if _, err := p.Run(); err != nil {
-			fmt.Printf("Alas, there's been an error: %v", err)
+	// Author filters (identities are resolved through .mailmap)
+	data.Filtered = data.Activity
+	if statsMeFlag {
+		email := git.GetConfig("user.email")
+		if email == "" {
+			fmt.Println("Error: --me requires git config user.email to be set.")
 			os.Exit(1)
 		}
-	},
+		_, email = git.CanonicalIdentity(git.GetConfig("user.name"), email)
+		data.AuthorLabel = email
+		data.Filtered = data.Activity.FilterEmail(email)
+	} else if statsAuthorFlag != "" {
+		data.AuthorLabel = statsAuthorFlag
+		data.Filtered = data.Activity.FilterAuthor(statsAuthorFlag)
+	}
+	return data
+}
+
+// calendarSettings resolves how commits are grouped into days from the flags,
+// falling back to git config (raven.stats.timezone, raven.stats.date and
+// raven.stats.weekStart). It exits on invalid values.
+func calendarSettings() (stats.Bucketing, time.Weekday) {
+	var b stats.Bucketing
+
+	zone := statsTimezone
+	if zone == "" {
+		zone = config.Get("stats.timezone", "local")
+	}
+	loc, err := stats.ParseTimezone(zone)
+	if err != nil {
+		fmt.Println("Error:", err)
+		os.Exit(1)
+	}
+	b.Location = loc
+
+	date := statsDateFlag
+	if date == "" {
+		date = config.Get("stats.date", "author")
+	}
+	switch date {
+	case "author":
+	case "committer":
+		b.Committer = true
+	default:
+		fmt.Printf("Error: unknown --date %q (use author or committer)\n", date)
+		os.Exit(1)
+	}
+
+	weekStart := statsWeekStart
+	if weekStart == "" {
+		weekStart = config.Get("stats.weekStart", "sunday")
+	}
+	first, err := stats.ParseWeekday(weekStart)
+	if err != nil {
+		fmt.Println("Error:", err)
+		os.Exit(1)
+	}
+	return b, first
+}
+
+// languageRules returns the built-in language rules extended by raven.language
+// entries of the form "<.ext|file name|#!interpreter>=<Language>".
+func languageRules() *stats.Languages {
+	langs := stats.DefaultLanguages()
+	for _, rule := range config.GetAll("language") {
+		pattern, name, ok := strings.Cut(rule, "=")
+		if !ok || pattern == "" || name == "" {
+			fmt.Printf("Warning: ignoring raven.language %q (expected <pattern>=<language>)\n", rule)
+			continue
+		}
+		langs.Set(strings.TrimSpace(pattern), strings.TrimSpace(name))
+	}
+	return langs
+}
+
+// collectRepos discovers repositories below roots and merges their history.
+func collectRepos(roots []string, opts stats.Options) stats.Activity {
+	seen := make(map[string]bool)
+	var repos []string
+	for _, root := range roots {
+		found, err := stats.DiscoverRepos(root, repoScanDepth)
+		if err != nil {
+			fmt.Printf("Warning: skipping %s: %v\n", root, err)
+			continue
+		}
+		for _, r := range found {
+			if !seen[r] {
+				seen[r] = true
+				repos = append(repos, r)
+			}
+		}
+	}
+	if len(repos) == 0 {
+		fmt.Println("Error: No git repositories found in", strings.Join(roots, ", "))
+		os.Exit(1)
+	}
+
+	activity, errs := stats.CollectRepos(repos, statsJobsFlag, !statsNoCache, opts)
+	for _, err := range errs {
+		fmt.Println("Warning:", err)
+	}
+	return activity
+}
+
+// parseDateFlag parses a YYYY-MM-DD flag value as a calendar day. Empty means unset.
+func parseDateFlag(name, value string) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, nil
+	}
+	t, err := stats.ParseDay(value)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid --%s date %q (expected YYYY-MM-DD)", name, value)
+	}
+	return t, nil
 }
 
 func init() {
+	statsCmd.PersistentFlags().StringVar(&statsSinceFlag, "since", "", "First day of the range (YYYY-MM-DD)")
+	statsCmd.PersistentFlags().StringVar(&statsUntilFlag, "until", "", "Last day of the range (YYYY-MM-DD, default today)")
+	statsCmd.Flags().BoolVar(&statsMonthFlag, "month", false, "Start in the month view instead of the year view")
+	statsCmd.PersistentFlags().StringVarP(&statsAuthorFlag, "author", "a", "", "Only count commits whose author name or email matches")
+	statsCmd.PersistentFlags().BoolVar(&statsMeFlag, "me", false, "Only count your own commits (from git config user.email)")
+	statsCmd.Flags().BoolVar(&statsSummary, "summary", false, "Print streaks, averages and activity distributions instead of the heatmap")
+	statsCmd.Flags().BoolVar(&statsJSON, "json", false, "Print the summary as JSON")
+	statsCmd.Flags().BoolVar(&statsLanguages, "languages", false, "Print the language and directory breakdown instead of the heatmap")
+	statsCmd.PersistentFlags().StringSliceVar(&statsReposFlag, "repos", nil, "Aggregate these repositories or folders of repositories (comma-separated)")
+	statsCmd.PersistentFlags().BoolVar(&statsWorkspace, "workspace", false, "Aggregate the repositories configured with raven.workspace")
+	statsCmd.PersistentFlags().IntVarP(&statsJobsFlag, "jobs", "j", min(runtime.NumCPU(), 8), "Maximum number of repositories read in parallel")
+	statsCmd.PersistentFlags().BoolVar(&statsNoCache, "no-cache", false, "Read the full history instead of the incremental cache in .git/raven/")
+	statsCmd.PersistentFlags().StringVar(&statsMetricFlag, "metric", "commits", "Heatmap metric: commits, added, deleted or net")
+	statsCmd.PersistentFlags().StringVar(&statsTimezone, "timezone", "", "Time zone of day boundaries: local, original (each commit's own offset), UTC or e.g. Europe/Berlin (default raven.stats.timezone or local)")
+	statsCmd.PersistentFlags().StringVar(&statsDateFlag, "date", "", "Date to group commits by: author or committer (default raven.stats.date or author)")
+	statsCmd.PersistentFlags().StringVar(&statsWeekStart, "week-start", "", "First day of the week, e.g. monday (default raven.stats.weekStart or sunday)")
 	rootCmd.AddCommand(statsCmd)
 }
@@ -0,0 +1,97 @@
+package cli
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strings"
+
+	"raven/internal/export"
+	"raven/internal/stats"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/spf13/cobra"
+)
+
+var (
+	exportFormatFlag string
+	exportOutputFlag string
+	exportThemeFlag  string
+)
+
+var statsExportCmd = &cobra.Command{
+	Use:   "export",
+	Short: "Export the contribution heatmap as SVG, PNG, HTML or CSV",
+	Long:  "Renders the same data and color buckets as 'raven stats' into a standalone file. Everything is generated offline.",
+	Run: func(cmd *cobra.Command, args []string) {
+		theme, ok := export.Themes[exportThemeFlag]
+		if !ok {
+			fmt.Printf("Error: unknown --theme %q (use dark or light)\n", exportThemeFlag)
+			os.Exit(1)
+		}
+		if !slices.Contains(export.Formats, exportFormatFlag) {
+			fmt.Printf("Error: unknown --format %q (use %s)\n", exportFormatFlag, strings.Join(export.Formats, ", "))
+			os.Exit(1)
+		}
+
+		data := loadStats()
+
+		// Default range: the last year, like the terminal view
+		end := data.Until
+		start := data.Since
+		if start.IsZero() {
+			start = stats.StartOfWeek(end, data.WeekStart).AddDate(0, 0, -7*52)
+		}
+
+		title := "Contributions"
+		if data.AuthorLabel != "" {
+			title += " of " + data.AuthorLabel
+		}
+		heatmap := export.New(data.Filtered, data.Metric, start, end, theme, title)
+		heatmap.WeekStart = data.WeekStart
+
+		output := exportOutputFlag
+		if output == "" {
+			output = "raven-heatmap." + exportFormatFlag
+		}
+
+		var w io.Writer = os.Stdout
+		var f *os.File
+		if output != "-" {
+			var err error
+			f, err = os.Create(output)
+			if err != nil {
+				fmt.Println("Error creating file:", err)
+				os.Exit(1)
+			}
+			w = f
+		}
+
+		err := heatmap.Write(w, exportFormatFlag)
+		if f != nil {
+			info, statErr := f.Stat()
+			if closeErr := f.Close(); err == nil {
+				err = closeErr
+			}
+			// Don't leave a partial file behind (but never remove a device)
+			if err != nil && statErr == nil && info.Mode().IsRegular() {
+				os.Remove(output)
+			}
+		}
+		if err != nil {
+			fmt.Println("Error exporting heatmap:", err)
+			os.Exit(1)
+		}
+		if output != "-" {
+			fmt.Println(lipgloss.NewStyle().Foreground(lipgloss.Color("#38BDF8")).Bold(true).Render("✔ Exported heatmap to " + output))
+		}
+	},
+}
+
+func init() {
+	statsExportCmd.Flags().StringVarP(&exportFormatFlag, "format", "f", "svg", "Output format: "+strings.Join(export.Formats, ", "))
+	statsExportCmd.Flags().StringVarP(&exportOutputFlag, "output", "o", "", "Output file ('-' for stdout, default raven-heatmap.<format>)")
+	statsExportCmd.Flags().StringVar(&exportThemeFlag, "theme", "dark", "Color theme: dark or light")
+	statsCmd.AddCommand(statsExportCmd)
+}
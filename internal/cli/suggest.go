@@ -4,7 +4,6 @@ import (
 	"fmt"
 	"os"
 
-	"raven/internal/analysis"
 	"raven/internal/git"
 
 	"github.com/charmbracelet/lipgloss"
@@ -39,15 +38,13 @@ var suggestCmd = &cobra.Command{
 			return
 		}
 
-		suggestion := analysis.AnalyzeDiff(diff)
+		suggestion := suggestCommit(diff)
 
-		// Format: type(scope): description
-		// If scope is empty, omit parens
-		msg := ""
-		if suggestion.Scope != "" {
-			msg = fmt.Sprintf("%s(%s): %s", suggestion.Type, suggestion.Scope, suggestion.Description)
-		} else {
-			msg = fmt.Sprintf("%s: %s", suggestion.Type, suggestion.Description)
+		// Format: type(scope): description, followed by the branch issue trailer
+		// using the first raven.trailer key (e.g. Refs: JIRA-123)
+		msg := suggestion.Header()
+		if suggestion.Issue != "" {
+			msg += "\n\n" + trailerKeys()[0] + ": " + suggestion.Issue
 		}
 
 		// Rich UI Output
@@ -0,0 +1,185 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+
+	"raven/internal/analysis"
+	"raven/internal/config"
+	"raven/internal/git"
+	"raven/internal/undo"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/spf13/cobra"
+)
+
+var (
+	syncRebaseFlag bool
+	syncMergeFlag  bool
+	syncNoPushFlag bool
+)
+
+// maxListed is how many incoming or outgoing commits sync prints.
+const maxListed = 10
+
+var syncCmd = &cobra.Command{
+	Use:     "sync",
+	Aliases: []string{"sy"},
+	Short:   "Fetch, integrate the upstream and push in one step",
+	Long: `Fetches the upstream of the current branch, integrates its new commits by
+rebasing your local commits on top (or merging, with --merge or
+raven.sync.strategy=merge), then pushes your local commits. Uncommitted
+changes are stashed and restored around the integration.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if !git.IsRepository() {
+			fmt.Println("Error: This is not a git repository.")
+			os.Exit(1)
+		}
+		if git.GetRebaseState().InProgress {
+			fmt.Println("Error: A rebase is in progress. Finish it with 'raven rebase --continue' or '--abort' first.")
+			os.Exit(1)
+		}
+
+		remote, branch := git.UpstreamBranch()
+		upstream := git.Upstream()
+		if remote == "" || upstream == "" {
+			fmt.Println("Error: The current branch has no upstream. Publish it with 'raven push'.")
+			os.Exit(1)
+		}
+
+		if remote != "." {
+			fmt.Printf("Fetching %s...\n", remote)
+			if err := git.Fetch(remote); err != nil {
+				fmt.Println("Error fetching:", err)
+				os.Exit(1)
+			}
+		}
+		ahead, behind, err := git.AheadBehind(upstream)
+		if err != nil {
+			fmt.Println("Error comparing with the upstream:", err)
+			os.Exit(1)
+		}
+
+		done := lipgloss.NewStyle().Foreground(lipgloss.Color("#38BDF8")).Bold(true)
+		if ahead == 0 && behind == 0 {
+			fmt.Println(done.Render("✔ Already in sync with " + upstream + "."))
+			return
+		}
+
+		if behind > 0 {
+			incoming, _ := git.GetMessages("HEAD.." + upstream)
+			listCommits(fmt.Sprintf("↓ %d incoming from %s:", behind, upstream), incoming, behind)
+			if ahead > 0 {
+				fmt.Println(lipgloss.NewStyle().Foreground(lipgloss.Color("#F59E0B")).Bold(true).
+					Render(fmt.Sprintf("⑂ Diverged: %d local and %d incoming commit(s).", ahead, behind)))
+			}
+			integrate(upstream, ahead)
+		}
+
+		ahead, _, _ = git.AheadBehind(upstream)
+		if ahead == 0 {
+			return
+		}
+		outgoing, _ := git.GetMessages(upstream + "..HEAD")
+		listCommits(fmt.Sprintf("↑ %d outgoing to %s:", ahead, upstream), outgoing, ahead)
+		if syncNoPushFlag {
+			fmt.Println("Not pushing (--no-push).")
+			return
+		}
+		if remote == "." {
+			fmt.Printf("Not pushing: the upstream %s is a local branch.\n", upstream)
+			return
+		}
+		if err := git.Push(remote, branch, false); err != nil {
+			fmt.Println("Error pushing:", err)
+			fmt.Println("Someone may have pushed in the meantime; run 'raven sync' again.")
+			os.Exit(1)
+		}
+		fmt.Println(done.Render(fmt.Sprintf("✔ Pushed %d commit(s) to %s.", ahead, upstream)))
+	},
+}
+
+// syncStrategy returns "rebase" or "merge" from the flags or raven.sync.strategy.
+func syncStrategy() string {
+	switch {
+	case syncMergeFlag:
+		return "merge"
+	case syncRebaseFlag:
+		return "rebase"
+	}
+	if config.Get("sync.strategy", "rebase") == "merge" {
+		return "merge"
+	}
+	return "rebase"
+}
+
+// integrate brings the upstream commits into the current branch, stashing
+// uncommitted changes around it. On a conflict it explains how to go on and
+// exits.
+func integrate(upstream string, ahead int) {
+	strategy := syncStrategy()
+	op := undo.Begin("sync")
+	result, err := git.Integrate(upstream, strategy == "merge", op.Git)
+
+	switch result.Stopped {
+	case "rebase":
+		fmt.Print(result.Output)
+		reportRebase(nil, "", err)
+		os.Exit(1)
+	case "merge":
+		fmt.Print(result.Output)
+		fmt.Println(lipgloss.NewStyle().Foreground(lipgloss.Color("#F472B6")).Bold(true).Render("✘ Conflict merging " + upstream))
+		for _, f := range result.Conflicts {
+			fmt.Println("    " + f)
+		}
+		fmt.Println("Resolve the conflicts, 'git add' the files, then run 'git merge --continue' and 'raven sync'.")
+		fmt.Println("Or 'git merge --abort' to go back.")
+		os.Exit(1)
+	}
+	if err != nil {
+		fmt.Print(result.Output)
+		fmt.Printf("Error integrating %s: %v\n", upstream, err)
+		os.Exit(1)
+	}
+	recordOperation(op)
+
+	done := lipgloss.NewStyle().Foreground(lipgloss.Color("#38BDF8")).Bold(true)
+	switch {
+	case ahead == 0:
+		fmt.Println(done.Render("✔ Fast-forwarded to " + upstream + "."))
+	case strategy == "merge":
+		fmt.Println(done.Render("✔ Merged " + upstream + "."))
+	default:
+		fmt.Println(done.Render(fmt.Sprintf("✔ Rebased %d local commit(s) onto %s.", ahead, upstream)))
+	}
+	switch {
+	case result.StashConflict:
+		fmt.Println(lipgloss.NewStyle().Foreground(lipgloss.Color("#F472B6")).
+			Render("⚠ Your uncommitted changes conflict with the new commits; they are kept in 'raven stash list'."))
+	case result.Stashed:
+		fmt.Println("Your uncommitted changes were stashed and restored.")
+	}
+}
+
+// listCommits prints a heading and up to maxListed commits. commits leaves
+// out merges, which are counted in total.
+func listCommits(heading string, commits []git.Message, total int) {
+	fmt.Println(lipgloss.NewStyle().Bold(true).Render(heading))
+	for i, c := range commits {
+		if i == maxListed {
+			fmt.Printf("    ... and %d more\n", len(commits)-maxListed)
+			break
+		}
+		fmt.Printf("    %s %s\n", c.ShortHash, analysis.FirstLine(c.Text))
+	}
+	if merges := total - len(commits); merges > 0 {
+		fmt.Printf("    and %d merge commit(s)\n", merges)
+	}
+}
+
+func init() {
+	syncCmd.Flags().BoolVar(&syncRebaseFlag, "rebase", false, "Rebase local commits onto the upstream (default)")
+	syncCmd.Flags().BoolVar(&syncMergeFlag, "merge", false, "Merge the upstream instead of rebasing")
+	syncCmd.Flags().BoolVar(&syncNoPushFlag, "no-push", false, "Only fetch and integrate; do not push")
+	rootCmd.AddCommand(syncCmd)
+}
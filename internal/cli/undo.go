@@ -3,37 +3,197 @@ package cli
 import (
 	"fmt"
 	"os"
-	"os/exec"
+	"strings"
 
 	"raven/internal/git"
+	"raven/internal/ui"
+	"raven/internal/undo"
 
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
 	"github.com/spf13/cobra"
 )
 
+var (
+	undoListFlag bool
+	undoYesFlag  bool
+)
+
 var undoCmd = &cobra.Command{
 	Use:     "undo",
 	Aliases: []string{"u"},
-	Short:   "Undo the last commit (keeps changes staged)",
-	Long:    "Executes 'git reset --soft HEAD~1', effectively un-committing the last commit while keeping the changes staged.",
+	Short:   "Undo the last operation (keeps your changes)",
+	Long: `Undoes raven's last operation on the current branch: commit, save, fix, amend or
+redo. The branch and the index go back to how they were before it; the working
+tree is never touched. Repeat to undo further, and use 'raven redo' to take an
+undo back. A commit made outside raven is undone from the branch reflog.
+
+Use --list to pick any recent step of the branch reflog and go back before it.`,
 	Run: func(cmd *cobra.Command, args []string) {
 		if !git.IsRepository() {
 			fmt.Println("Error: This is not a git repository.")
 			os.Exit(1)
 		}
 
-		// Run git reset --soft HEAD~1
-		c := exec.Command("git", "reset", "--soft", "HEAD~1")
-		// We capture stderr in case of error (e.g., no commits yet)
-		output, err := c.CombinedOutput()
+		journal, err := undo.Load()
 		if err != nil {
-			fmt.Printf("Error undoing commit (maybe no commits exists?):\n%s\n", string(output))
+			fmt.Println("Warning: could not read the undo journal:", err)
+		}
+		ref := git.HeadRef()
+
+		if undoListFlag {
+			undoFromList(ref, journal)
+			return
+		}
+
+		head := git.RevParse(ref)
+		lease := git.RevParse("@{upstream}")
+		if e, ok := journal.NextUndo(ref); ok && e.After == head {
+			if !guardRewrite("Undoing", head) || !confirm(fmt.Sprintf("Undo raven %s (%s %s)?", e.Op, undo.Short(e.After), e.Subject)) {
+				fmt.Println("Aborted.")
+				return
+			}
+			restored, err := undo.Undo(e)
+			reportRestore("Undid raven "+e.Op, ref, restored, err)
+			pushRewritten(lease)
+			return
+		}
+
+		// HEAD was moved outside raven: undo the last move of the branch reflog.
+		steps, err := undo.Steps(ref, 1, journal)
+		if err != nil || len(steps) == 0 || steps[0].Entry != nil || !steps[0].Undoable {
+			fmt.Println("Nothing to undo. Use 'raven undo --list' to go back further.")
+			return
+		}
+		if !guardRewrite("Undoing", head) || !confirm(fmt.Sprintf("Undo %q?", steps[0].Message)) {
+			fmt.Println("Aborted.")
+			return
+		}
+		restored, err := undo.UndoSteps(ref, steps, 0)
+		reportRestore("Undid "+steps[0].Message, ref, restored, err)
+		pushRewritten(lease)
+	},
+}
+
+var redoCmd = &cobra.Command{
+	Use:   "redo",
+	Short: "Redo the last undo",
+	Long:  "Takes back the last 'raven undo' on the current branch, as long as nothing was committed since.",
+	Run: func(cmd *cobra.Command, args []string) {
+		if !git.IsRepository() {
+			fmt.Println("Error: This is not a git repository.")
 			os.Exit(1)
 		}
 
-		fmt.Println("✔ Undid last commit. Changes are now staged.")
+		journal, err := undo.Load()
+		if err != nil {
+			fmt.Println("Error reading the undo journal:", err)
+			os.Exit(1)
+		}
+		ref := git.HeadRef()
+		u, ok := journal.NextRedo(ref)
+		if !ok {
+			fmt.Println("Nothing to redo.")
+			return
+		}
+		if u.After != git.RevParse(ref) {
+			fmt.Println("Error: HEAD has moved since the last undo; nothing to redo.")
+			os.Exit(1)
+		}
+		if !guardRewrite("Redoing", u.After) {
+			fmt.Println("Aborted.")
+			return
+		}
+		lease := git.RevParse("@{upstream}")
+		restored, err := undo.Redo(u)
+		reportRestore("Redid "+describeTarget(journal, u), ref, restored, err)
+		pushRewritten(lease)
 	},
 }
 
+// undoFromList lets the user pick a step of the branch reflog and undoes
+// everything up to it.
+func undoFromList(ref string, journal undo.Journal) {
+	steps, err := undo.Steps(ref, 30, journal)
+	if err != nil || len(steps) == 0 {
+		fmt.Println("Nothing to undo.")
+		return
+	}
+	branch := strings.TrimPrefix(ref, "refs/heads/")
+	m, err := tea.NewProgram(ui.InitialUndoModel(branch, steps)).Run()
+	if err != nil {
+		fmt.Println("Error running UI:", err)
+		os.Exit(1)
+	}
+	chosen := m.(ui.UndoModel).Chosen
+	if chosen < 0 {
+		return
+	}
+	if !guardRewrite("Undoing", steps[0].After) {
+		fmt.Println("Aborted.")
+		return
+	}
+	lease := git.RevParse("@{upstream}")
+	if !confirm(fmt.Sprintf("Undo %d step(s), back to %s?", chosen+1, undo.Short(steps[chosen].Before))) {
+		fmt.Println("Aborted.")
+		return
+	}
+	restored, err := undo.UndoSteps(ref, steps, chosen)
+	reportRestore(fmt.Sprintf("Undid %d step(s)", chosen+1), ref, restored, err)
+	pushRewritten(lease)
+}
+
+// describeTarget names the operation an undo entry undid, e.g. "raven fix".
+func describeTarget(journal undo.Journal, u undo.Entry) string {
+	if e, ok := journal.Find(u.Target); ok {
+		return "raven " + e.Op
+	}
+	return "undo"
+}
+
+// reportRestore prints the outcome of an undo or redo.
+func reportRestore(done, ref string, indexRestored bool, err error) {
+	if err != nil {
+		fmt.Println("Error:", err)
+		os.Exit(1)
+	}
+	head := git.RevParse(ref)
+	where := "HEAD is now at " + undo.Short(head) + " " + git.Subject(head)
+	if head == "" {
+		where = "The branch has no commits now"
+	}
+	fmt.Println(lipgloss.NewStyle().Foreground(lipgloss.Color("#38BDF8")).Bold(true).Render("✔ " + done + "."))
+	fmt.Println(where + ".")
+	if indexRestored {
+		fmt.Println("The index is back as it was; your working tree is untouched.")
+	} else {
+		fmt.Println("Your changes are kept staged; the working tree is untouched.")
+	}
+}
+
+// confirm asks a y/N question, answered yes by --yes.
+func confirm(question string) bool {
+	if undoYesFlag {
+		return true
+	}
+	fmt.Print(question + " [y/N]: ")
+	var response string
+	fmt.Scanln(&response)
+	return response == "y" || response == "Y"
+}
+
+// recordOperation records a finished operation for undo, warning on failure.
+func recordOperation(op *undo.Operation) {
+	if err := op.Finish(); err != nil {
+		fmt.Println("Warning: could not record this operation for undo:", err)
+	}
+}
+
 func init() {
+	undoCmd.Flags().BoolVarP(&undoListFlag, "list", "l", false, "Pick a step of the branch reflog to go back before")
+	undoCmd.Flags().BoolVarP(&undoYesFlag, "yes", "y", false, "Do not ask for confirmation")
+	addRewriteFlags(undoCmd)
+	addRewriteFlags(redoCmd)
 	rootCmd.AddCommand(undoCmd)
+	rootCmd.AddCommand(redoCmd)
 }
@@ -0,0 +1,66 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"raven/internal/git"
+)
+
+// Raven settings live in git config under the "raven." section, so they can be
+// set per repository (git config raven.x y) or for all repos (git config --global).
+
+// Get returns the value of raven.<key>, or def if unset.
+func Get(key, def string) string {
+	if v := git.GetConfig("raven." + key); v != "" {
+		return v
+	}
+	return def
+}
+
+// GetAll returns every value of the multi-valued raven.<key>.
+func GetAll(key string) []string {
+	return git.GetConfigAll("raven." + key)
+}
+
+// GetBool returns raven.<key> as a boolean, or def if unset or invalid.
+func GetBool(key string, def bool) bool {
+	v, err := strconv.ParseBool(Get(key, ""))
+	if err != nil {
+		return def
+	}
+	return v
+}
+
+// GetInt returns raven.<key> as an integer, or def if unset or invalid.
+func GetInt(key string, def int) int {
+	v, err := strconv.Atoi(Get(key, ""))
+	if err != nil {
+		return def
+	}
+	return v
+}
+
+// Workspaces returns the directories configured with raven.workspace,
+// each of which is a repository or a folder containing repositories.
+func Workspaces() []string {
+	var dirs []string
+	for _, d := range GetAll("workspace") {
+		dirs = append(dirs, ExpandHome(d))
+	}
+	return dirs
+}
+
+// ExpandHome replaces a leading "~" with the user's home directory.
+func ExpandHome(path string) string {
+	if path != "~" && !strings.HasPrefix(path, "~/") {
+		return path
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return path
+	}
+	return filepath.Join(home, strings.TrimPrefix(path, "~"))
+}
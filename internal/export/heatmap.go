@@ -0,0 +1,248 @@
+package export
+
+import (
+	"encoding/csv"
+	"fmt"
+	"html"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/png"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"raven/internal/stats"
+)
+
+// Theme holds the colors of an exported heatmap.
+type Theme struct {
+	Background string
+	Text       string
+	Levels     []string // Heat levels 0 (none) to 5 (exceptional)
+}
+
+// Themes are the built-in export themes. "dark" matches the terminal heatmap.
+var Themes = map[string]Theme{
+	"dark": {
+		Background: "#0D1117",
+		Text:       "#9CA3AF",
+		Levels:     stats.HeatPalette,
+	},
+	"light": {
+		Background: "#FFFFFF",
+		Text:       "#57606A",
+		Levels:     []string{"#EBEDF0", "#BAE6FD", "#7DD3FC", "#38BDF8", "#0369A1", "#F59E0B"},
+	},
+}
+
+// Formats lists the supported export formats.
+var Formats = []string{"svg", "png", "html", "csv"}
+
+// Cell geometry shared by SVG and PNG output (in pixels).
+const (
+	cellSize  = 11
+	cellPitch = 14 // cell + gap
+	gutterX   = 32 // room for weekday labels
+	gutterY   = 20 // room for month labels
+)
+
+// Heatmap is the data of an exported contribution graph: one column per week
+// from Start to End, colored with the same buckets as the terminal view.
+type Heatmap struct {
+	Activity  stats.Activity
+	Metric    stats.Metric
+	Start     time.Time    // First day, inclusive
+	End       time.Time    // Last day, inclusive
+	WeekStart time.Weekday // First row of each week column
+	Theme     Theme
+	Title     string
+
+	buckets stats.Buckets
+}
+
+// New prepares a heatmap between start and end.
+func New(activity stats.Activity, metric stats.Metric, start, end time.Time, theme Theme, title string) Heatmap {
+	return Heatmap{
+		Activity: activity,
+		Metric:   metric,
+		Start:    stats.Date(start),
+		End:      stats.Date(end),
+		Theme:    theme,
+		Title:    title,
+		buckets:  activity.Scale(metric, start, end),
+	}
+}
+
+// Write renders the heatmap in the given format.
+func (h Heatmap) Write(w io.Writer, format string) error {
+	switch format {
+	case "svg":
+		return h.SVG(w)
+	case "png":
+		return h.PNG(w)
+	case "html":
+		return h.HTML(w)
+	case "csv":
+		return h.CSV(w)
+	}
+	return fmt.Errorf("unknown format %q (use %s)", format, strings.Join(Formats, ", "))
+}
+
+// cell is one day positioned in the grid.
+type cell struct {
+	Day   time.Time
+	Row   int // Position within the week
+	Week  int
+	Value int
+	Level int
+}
+
+func (h Heatmap) cells() []cell {
+	first := stats.StartOfWeek(h.Start, h.WeekStart)
+	var cells []cell
+	for _, day := range stats.Days(h.Start, h.End) {
+		v := h.Activity.Value(day.Format("2006-01-02"), h.Metric)
+		cells = append(cells, cell{
+			Day:   day,
+			Row:   stats.WeekdayIndex(day.Weekday(), h.WeekStart),
+			Week:  int(day.Sub(first).Hours()/24) / 7,
+			Value: v,
+			Level: h.buckets.Level(v),
+		})
+	}
+	return cells
+}
+
+func (h Heatmap) weeks() int {
+	return int(stats.StartOfWeek(h.End, h.WeekStart).Sub(stats.StartOfWeek(h.Start, h.WeekStart)).Hours()/24/7) + 1
+}
+
+func (h Heatmap) size() (int, int) {
+	return gutterX + h.weeks()*cellPitch, gutterY + 7*cellPitch
+}
+
+func (h Heatmap) level(l int) string {
+	if l >= len(h.Theme.Levels) {
+		l = len(h.Theme.Levels) - 1
+	}
+	return h.Theme.Levels[l]
+}
+
+// SVG writes a standalone SVG image with a tooltip per day.
+func (h Heatmap) SVG(w io.Writer) error {
+	width, height := h.size()
+	var b strings.Builder
+	fmt.Fprintf(&b, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" viewBox="0 0 %d %d" font-family="sans-serif" font-size="10">`+"\n", width, height, width, height)
+	fmt.Fprintf(&b, `<rect width="100%%" height="100%%" fill="%s"/>`+"\n", h.Theme.Background)
+
+	// Month labels over the first week of each month (and over the start,
+	// unless the next month's label would overlap it)
+	for _, c := range h.cells() {
+		if c.Day.Day() == 1 || (c.Day.Equal(h.Start) && c.Day.Day() <= 14) {
+			fmt.Fprintf(&b, `<text x="%d" y="%d" fill="%s">%s</text>`+"\n", gutterX+c.Week*cellPitch, gutterY-6, h.Theme.Text, c.Day.Format("Jan"))
+		}
+	}
+	// Weekday labels
+	for _, wd := range []time.Weekday{time.Monday, time.Wednesday, time.Friday} {
+		row := stats.WeekdayIndex(wd, h.WeekStart)
+		fmt.Fprintf(&b, `<text x="0" y="%d" fill="%s">%s</text>`+"\n", gutterY+row*cellPitch+cellSize-1, h.Theme.Text, wd.String()[:3])
+	}
+	for _, c := range h.cells() {
+		fmt.Fprintf(&b, `<rect x="%d" y="%d" width="%d" height="%d" rx="2" fill="%s"><title>%s: %s</title></rect>`+"\n",
+			gutterX+c.Week*cellPitch, gutterY+c.Row*cellPitch, cellSize, cellSize,
+			h.level(c.Level), c.Day.Format("Mon Jan 02 2006"), h.Metric.Describe(c.Value))
+	}
+	b.WriteString("</svg>\n")
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// PNG writes the grid as a bitmap. Labels are omitted to stay free of font dependencies.
+func (h Heatmap) PNG(w io.Writer) error {
+	width, height := h.size()
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	draw.Draw(img, img.Bounds(), &image.Uniform{parseHex(h.Theme.Background)}, image.Point{}, draw.Src)
+
+	for _, c := range h.cells() {
+		x := gutterX + c.Week*cellPitch
+		y := gutterY + c.Row*cellPitch
+		r := image.Rect(x, y, x+cellSize, y+cellSize)
+		draw.Draw(img, r, &image.Uniform{parseHex(h.level(c.Level))}, image.Point{}, draw.Src)
+	}
+	return png.Encode(w, img)
+}
+
+// HTML writes a standalone page with the SVG, a legend and totals.
+func (h Heatmap) HTML(w io.Writer) error {
+	var svg strings.Builder
+	if err := h.SVG(&svg); err != nil {
+		return err
+	}
+
+	total := 0
+	for _, c := range h.cells() {
+		total += c.Value
+	}
+
+	var legend strings.Builder
+	for l := range h.Theme.Levels {
+		fmt.Fprintf(&legend, `<span class="cell" style="background:%s"></span>`, h.level(l))
+	}
+
+	_, err := fmt.Fprintf(w, `<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>%s</title>
+<style>
+body { background: %s; color: %s; font-family: sans-serif; margin: 2em; }
+h1 { font-size: 1.2em; }
+.legend { margin-top: 0.5em; font-size: 0.8em; }
+.cell { display: inline-block; width: 11px; height: 11px; border-radius: 2px; margin: 0 1px; vertical-align: middle; }
+</style>
+</head>
+<body>
+<h1>%s</h1>
+<p>%s – %s: %s</p>
+%s
+<div class="legend">Less %s More</div>
+</body>
+</html>
+`, html.EscapeString(h.Title), h.Theme.Background, h.Theme.Text, html.EscapeString(h.Title),
+		h.Start.Format("Jan 02 2006"), h.End.Format("Jan 02 2006"), h.Metric.Describe(total),
+		svg.String(), legend.String())
+	return err
+}
+
+// CSV writes one row per day with every metric and the heat level.
+func (h Heatmap) CSV(w io.Writer) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write([]string{"date", "commits", "added", "deleted", "net", "level"}); err != nil {
+		return err
+	}
+	for _, c := range h.cells() {
+		key := c.Day.Format("2006-01-02")
+		row := []string{key}
+		for _, m := range stats.Metrics {
+			row = append(row, strconv.Itoa(h.Activity.Value(key, m)))
+		}
+		row = append(row, strconv.Itoa(c.Level))
+		if err := cw.Write(row); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// parseHex parses "#RRGGBB" into a color, falling back to black.
+func parseHex(s string) color.RGBA {
+	var r, g, b uint8
+	if _, err := fmt.Sscanf(s, "#%02x%02x%02x", &r, &g, &b); err != nil {
+		return color.RGBA{A: 255}
+	}
+	return color.RGBA{R: r, G: g, B: b, A: 255}
+}
@@ -0,0 +1,89 @@
+package export
+
+import (
+	"bytes"
+	"image/png"
+	"strings"
+	"testing"
+	"time"
+
+	"raven/internal/stats"
+)
+
+func testHeatmap() Heatmap {
+	activity := stats.Activity{
+		"2024-03-04": {{Commits: 1, Additions: 10}},
+		"2024-03-05": {{Commits: 2}},
+	}
+	start := time.Date(2024, 3, 3, 0, 0, 0, 0, time.Local)
+	end := time.Date(2024, 3, 16, 0, 0, 0, 0, time.Local)
+	return New(activity, stats.MetricCommits, start, end, Themes["light"], "Test")
+}
+
+func TestSVG(t *testing.T) {
+	var b bytes.Buffer
+	if err := testHeatmap().SVG(&b); err != nil {
+		t.Fatal(err)
+	}
+	svg := b.String()
+	if n := strings.Count(svg, "<rect x="); n != 14 {
+		t.Errorf("got %d day cells, want 14", n)
+	}
+	if !strings.Contains(svg, "Tue Mar 05 2024: 2 commits") {
+		t.Error("missing tooltip for Mar 05")
+	}
+	if !strings.Contains(svg, Themes["light"].Levels[0]) {
+		t.Error("empty days should use the level 0 color")
+	}
+}
+
+func TestPNG(t *testing.T) {
+	var b bytes.Buffer
+	h := testHeatmap()
+	if err := h.PNG(&b); err != nil {
+		t.Fatal(err)
+	}
+	img, err := png.Decode(&b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	w, ht := h.size()
+	if img.Bounds().Dx() != w || img.Bounds().Dy() != ht {
+		t.Errorf("got %v, want %dx%d", img.Bounds(), w, ht)
+	}
+}
+
+func TestCSV(t *testing.T) {
+	var b bytes.Buffer
+	if err := testHeatmap().CSV(&b); err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSpace(b.String()), "\n")
+	if len(lines) != 15 {
+		t.Fatalf("got %d lines, want header + 14 days", len(lines))
+	}
+	if !strings.HasPrefix(lines[2], "2024-03-04,1,10,0,10,") {
+		t.Errorf("unexpected row %q", lines[2])
+	}
+}
+
+func TestWriteUnknownFormat(t *testing.T) {
+	if err := testHeatmap().Write(&bytes.Buffer{}, "bmp"); err == nil {
+		t.Error("expected an error for an unknown format")
+	}
+}
+
+func TestWeekStart(t *testing.T) {
+	h := testHeatmap() // Sunday Mar 3 to Saturday Mar 16
+	h.WeekStart = time.Monday
+	cells := h.cells()
+	if cells[0].Row != 6 || cells[0].Week != 0 {
+		t.Errorf("expected Sunday in the last row of the first week, got %+v", cells[0])
+	}
+	if cells[1].Row != 0 || cells[1].Week != 1 {
+		t.Errorf("expected Monday to start the second week, got %+v", cells[1])
+	}
+	if h.weeks() != 3 {
+		t.Errorf("expected 3 week columns, got %d", h.weeks())
+	}
+}
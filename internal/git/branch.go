@@ -0,0 +1,184 @@
+package git
+
+import (
+	"errors"
+	"os/exec"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Branch is a local or remote-tracking branch.
+type Branch struct {
+	Name     string // "main" or "origin/main"
+	Remote   bool
+	Current  bool
+	Upstream string // Upstream of a local branch, "" if none
+	Gone     bool   // The upstream was deleted on the remote
+	Ahead    int    // Commits not on the upstream
+	Behind   int    // Upstream commits not on the branch
+	Merged   bool   // Fully merged into the base branch
+	Date     time.Time
+	Subject  string
+}
+
+// GetBranches lists local branches followed by remote-tracking branches,
+// each group newest first. Merged is computed against base.
+func GetBranches(base string) ([]Branch, error) {
+	format := "--format=" + strings.Join([]string{
+		"%(refname)", "%(refname:short)", "%(HEAD)", "%(upstream:short)",
+		"%(upstream:track,nobracket)", "%(committerdate:unix)", "%(contents:subject)",
+	}, fieldSep)
+	cmd := exec.Command("git", "for-each-ref", "--sort=-committerdate", format, "refs/heads", "refs/remotes")
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, err
+	}
+
+	merged := mergedRefs(base)
+	var local, remote []Branch
+	for _, line := range strings.Split(strings.TrimSpace(string(out)), "\n") {
+		fields := strings.Split(line, fieldSep)
+		if len(fields) < 7 {
+			continue
+		}
+		ref := fields[0]
+		if strings.HasSuffix(ref, "/HEAD") {
+			continue // origin/HEAD is a symbolic alias
+		}
+		unix, _ := strconv.ParseInt(fields[5], 10, 64)
+		b := Branch{
+			Name:     fields[1],
+			Remote:   strings.HasPrefix(ref, "refs/remotes/"),
+			Current:  fields[2] == "*",
+			Upstream: fields[3],
+			Merged:   merged[ref],
+			Date:     time.Unix(unix, 0),
+			Subject:  strings.Join(fields[6:], fieldSep),
+		}
+		b.Ahead, b.Behind, b.Gone = parseTrack(fields[4])
+		if b.Remote {
+			remote = append(remote, b)
+		} else {
+			local = append(local, b)
+		}
+	}
+	return append(local, remote...), nil
+}
+
+// parseTrack parses %(upstream:track,nobracket), e.g. "ahead 2, behind 1" or "gone".
+func parseTrack(track string) (ahead, behind int, gone bool) {
+	if track == "gone" {
+		return 0, 0, true
+	}
+	for _, part := range strings.Split(track, ",") {
+		kind, n, ok := strings.Cut(strings.TrimSpace(part), " ")
+		if !ok {
+			continue
+		}
+		count, _ := strconv.Atoi(n)
+		switch kind {
+		case "ahead":
+			ahead = count
+		case "behind":
+			behind = count
+		}
+	}
+	return ahead, behind, false
+}
+
+// mergedRefs returns the full ref names of branches merged into base.
+func mergedRefs(base string) map[string]bool {
+	merged := make(map[string]bool)
+	if base == "" {
+		return merged
+	}
+	cmd := exec.Command("git", "for-each-ref", "--merged", base, "--format=%(refname)", "refs/heads", "refs/remotes")
+	out, err := cmd.Output()
+	if err != nil {
+		return merged
+	}
+	for _, ref := range strings.Fields(string(out)) {
+		merged[ref] = true
+	}
+	return merged
+}
+
+// DefaultBranch guesses the main line of the repository: the remote HEAD
+// (origin/HEAD), else a local "main" or "master". It returns "" if none exists.
+func DefaultBranch() string {
+	cmd := exec.Command("git", "symbolic-ref", "--quiet", "--short", "refs/remotes/origin/HEAD")
+	if out, err := cmd.Output(); err == nil {
+		return strings.TrimPrefix(strings.TrimSpace(string(out)), "origin/")
+	}
+	for _, name := range []string{"main", "master"} {
+		if exec.Command("git", "show-ref", "--verify", "--quiet", "refs/heads/"+name).Run() == nil {
+			return name
+		}
+	}
+	return ""
+}
+
+// CurrentBranch returns the checked out branch, or "" on a detached HEAD.
+func CurrentBranch() string {
+	cmd := exec.Command("git", "symbolic-ref", "--quiet", "--short", "HEAD")
+	out, err := cmd.Output()
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(out))
+}
+
+// Checkout switches to a local branch.
+func Checkout(name string) error {
+	return run("checkout", name)
+}
+
+// CheckoutRemote creates a local branch tracking a remote branch ("origin/x")
+// and switches to it.
+func CheckoutRemote(remoteBranch string) error {
+	return run("checkout", "--track", remoteBranch)
+}
+
+// CreateBranch creates a branch from HEAD and switches to it.
+func CreateBranch(name string) error {
+	return run("checkout", "-b", name)
+}
+
+// RenameBranch renames a local branch.
+func RenameBranch(oldName, newName string) error {
+	return run("branch", "-m", oldName, newName)
+}
+
+// DeleteBranch deletes a local branch. git refuses if it is not fully merged.
+func DeleteBranch(name string) error {
+	return run("branch", "-d", name)
+}
+
+// SetUpstream sets the upstream of a local branch, e.g. "origin/feat/x".
+func SetUpstream(name, upstream string) error {
+	return run("branch", "--set-upstream-to="+upstream, name)
+}
+
+// run executes a git command and returns git's own message on failure.
+func run(args ...string) error {
+	cmd := exec.Command("git", args...)
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		if msg := strings.TrimSpace(string(out)); msg != "" {
+			return errors.New(msg)
+		}
+		return err
+	}
+	return nil
+}
+
+// Upstream returns the upstream of the current branch ("origin/main"), or "" if none.
+func Upstream() string {
+	cmd := exec.Command("git", "rev-parse", "--abbrev-ref", "--symbolic-full-name", "@{upstream}")
+	out, err := cmd.Output()
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(out))
+}
@@ -2,6 +2,7 @@ package git
 
 import (
 	"os/exec"
+	"strings"
 )
 
 // IsRepository checks if the current directory is within a git repository.
@@ -22,3 +23,55 @@ func GetStagedDiff() (string, error) {
 	}
 	return string(out), nil
 }
+
+// GetConfig returns the value of a git config key, or "" if it is unset.
+func GetConfig(key string) string {
+	cmd := exec.Command("git", "config", "--get", key)
+	out, err := cmd.Output()
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(out))
+}
+
+// GetConfigAll returns every value of a multi-valued git config key.
+func GetConfigAll(key string) []string {
+	cmd := exec.Command("git", "config", "--get-all", key)
+	out, err := cmd.Output()
+	if err != nil {
+		return nil
+	}
+	var values []string
+	for _, line := range strings.Split(string(out), "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			values = append(values, line)
+		}
+	}
+	return values
+}
+
+// CanonicalIdentity resolves a name and email through .mailmap.
+func CanonicalIdentity(name, email string) (string, string) {
+	cmd := exec.Command("git", "check-mailmap", name+" <"+email+">")
+	out, err := cmd.Output()
+	if err != nil {
+		return name, email
+	}
+	// Output: "Canonical Name <canonical@email>"
+	line := strings.TrimSpace(string(out))
+	open := strings.LastIndex(line, "<")
+	if open < 0 || !strings.HasSuffix(line, ">") {
+		return name, email
+	}
+	return strings.TrimSpace(line[:open]), line[open+1 : len(line)-1]
+}
+
+// TopLevel returns the root directory of the working tree.
+func TopLevel() (string, error) {
+	cmd := exec.Command("git", "rev-parse", "--show-toplevel")
+	out, err := cmd.Output()
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(string(out)), nil
+}
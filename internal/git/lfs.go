@@ -0,0 +1,66 @@
+package git
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// Unstage removes a path from the index, back to its HEAD version (or
+// untracked, for a new file). Unlike UnstageFile it works before the first
+// commit.
+func Unstage(path string) error {
+	return run("reset", "-q", "--", path)
+}
+
+// Ignore appends a pattern to the .gitignore at the repository root, unless
+// it is already there, and stages .gitignore.
+func Ignore(pattern string) error {
+	root, err := TopLevel()
+	if err != nil {
+		return err
+	}
+	file := filepath.Join(root, ".gitignore")
+	data, err := os.ReadFile(file)
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	for _, line := range strings.Split(string(data), "\n") {
+		if strings.TrimSpace(line) == pattern {
+			return run("add", file)
+		}
+	}
+	text := string(data)
+	if text != "" && !strings.HasSuffix(text, "\n") {
+		text += "\n"
+	}
+	if err := os.WriteFile(file, []byte(text+pattern+"\n"), 0o644); err != nil {
+		return err
+	}
+	return run("add", file)
+}
+
+// LFSAvailable reports whether git LFS is installed.
+func LFSAvailable() bool {
+	return exec.Command("git", "lfs", "version").Run() == nil
+}
+
+// LFSTrack tracks pattern with git LFS and stages path again so that its
+// content goes to LFS. .gitattributes is staged too.
+func LFSTrack(pattern, path string) error {
+	if err := run("lfs", "track", pattern); err != nil {
+		return err
+	}
+	root, err := TopLevel()
+	if err != nil {
+		return err
+	}
+	if err := run("add", filepath.Join(root, ".gitattributes")); err != nil {
+		return err
+	}
+	if err := run("rm", "--cached", "-q", "--", path); err != nil {
+		return err
+	}
+	return run("add", "--", path)
+}
@@ -0,0 +1,197 @@
+package git
+
+import (
+	"os/exec"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Commit holds the metadata of a single commit.
+type Commit struct {
+	Hash      string
+	ShortHash string
+	Parents   []string
+	Author    string
+	Email     string
+	Date      time.Time
+	Subject   string
+}
+
+// LogEntry is one rendered line of `git log --graph`.
+// Commit is nil for lines that only carry graph edges.
+type LogEntry struct {
+	Graph  string
+	Commit *Commit
+}
+
+// LogOptions narrows down the commits returned by GetLog.
+type LogOptions struct {
+	Author string   // --author pattern
+	Since  string   // --since date
+	Until  string   // --until date
+	Grep   []string // extended regexes that must all match the message
+	Paths  []string // limit to commits touching these paths
+	Max    int      // 0 means no limit
+	Graph  bool     // include graph lines
+}
+
+// Field/record separators that never appear in commit metadata.
+const (
+	recordSep = "\x1e"
+	fieldSep  = "\x1f"
+)
+
+const logFormat = "--pretty=format:" + recordSep + "%H" + fieldSep + "%h" + fieldSep + "%P" + fieldSep + "%an" + fieldSep + "%ae" + fieldSep + "%at" + fieldSep + "%s"
+
+// GetLog returns the commit history matching opts, newest first.
+func GetLog(opts LogOptions) ([]LogEntry, error) {
+	args := []string{"log", logFormat}
+	if opts.Graph {
+		args = append(args, "--graph")
+	}
+	if opts.Author != "" {
+		args = append(args, "--author="+opts.Author)
+	}
+	if opts.Since != "" {
+		args = append(args, "--since="+opts.Since)
+	}
+	if opts.Until != "" {
+		args = append(args, "--until="+opts.Until)
+	}
+	if len(opts.Grep) > 0 {
+		args = append(args, "--extended-regexp", "--all-match")
+		for _, g := range opts.Grep {
+			args = append(args, "--grep="+g)
+		}
+	}
+	if opts.Max > 0 {
+		args = append(args, "-n", strconv.Itoa(opts.Max))
+	}
+	if len(opts.Paths) > 0 {
+		args = append(args, "--")
+		args = append(args, opts.Paths...)
+	}
+
+	cmd := exec.Command("git", args...)
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, err
+	}
+	return parseLog(string(out)), nil
+}
+
+func parseLog(out string) []LogEntry {
+	var entries []LogEntry
+	for _, line := range strings.Split(out, "\n") {
+		if line == "" {
+			continue
+		}
+		idx := strings.Index(line, recordSep)
+		if idx < 0 {
+			// Graph-only line, e.g. "|\" or "| |/"
+			entries = append(entries, LogEntry{Graph: line})
+			continue
+		}
+		c := parseCommit(line[idx+len(recordSep):])
+		entries = append(entries, LogEntry{Graph: line[:idx], Commit: &c})
+	}
+	return entries
+}
+
+func parseCommit(record string) Commit {
+	fields := strings.SplitN(record, fieldSep, 7)
+	for len(fields) < 7 {
+		fields = append(fields, "")
+	}
+	unix, _ := strconv.ParseInt(fields[5], 10, 64)
+	return Commit{
+		Hash:      fields[0],
+		ShortHash: fields[1],
+		Parents:   strings.Fields(fields[2]),
+		Author:    fields[3],
+		Email:     fields[4],
+		Date:      time.Unix(unix, 0),
+		Subject:   fields[6],
+	}
+}
+
+// ShowCommit returns the full message, stat and patch of a commit.
+func ShowCommit(hash string) (string, error) {
+	return ShowCommitIn("", hash)
+}
+
+// ShowCommitIn is ShowCommit for the repository at dir ("" = current directory).
+func ShowCommitIn(dir, hash string) (string, error) {
+	cmd := exec.Command("git", "show", "--stat", "--patch", "--format=fuller", hash)
+	cmd.Dir = dir
+	out, err := cmd.Output()
+	if err != nil {
+		return "", err
+	}
+	return string(out), nil
+}
+
+// Message is the full message of a commit.
+type Message struct {
+	Hash      string
+	ShortHash string
+	Text      string // Subject, body and trailers
+}
+
+// GetMessages returns the full messages of the commits selected by the
+// given `git log` arguments (revision ranges, -n, ...), newest first.
+// Merge commits are skipped.
+func GetMessages(args ...string) ([]Message, error) {
+	format := "--pretty=format:" + recordSep + "%H" + fieldSep + "%h" + fieldSep + "%B"
+	cmd := exec.Command("git", append([]string{"log", "--no-merges", format}, args...)...)
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, err
+	}
+	var messages []Message
+	for _, record := range strings.Split(string(out), recordSep) {
+		fields := strings.SplitN(record, fieldSep, 3)
+		if len(fields) < 3 {
+			continue
+		}
+		messages = append(messages, Message{
+			Hash:      fields[0],
+			ShortHash: fields[1],
+			Text:      strings.TrimSpace(fields[2]),
+		})
+	}
+	return messages, nil
+}
+
+// CommitPatch returns the patch of a commit without context lines.
+func CommitPatch(hash string) (string, error) {
+	cmd := exec.Command("git", "show", "--format=", "--no-color", "-U0", hash)
+	out, err := cmd.Output()
+	if err != nil {
+		return "", err
+	}
+	return string(out), nil
+}
+
+// DiffStat counts the files changed and the lines added and deleted in a
+// diff range such as "main...HEAD". Binary files count without lines.
+func DiffStat(spec string) (files, added, deleted int, err error) {
+	cmd := exec.Command("git", "diff", "--numstat", spec)
+	out, err := cmd.Output()
+	if err != nil {
+		return 0, 0, 0, err
+	}
+	for _, line := range strings.Split(strings.TrimSpace(string(out)), "\n") {
+		fields := strings.SplitN(line, "\t", 3)
+		if len(fields) < 3 {
+			continue
+		}
+		files++
+		a, _ := strconv.Atoi(fields[0]) // "-" for binary files
+		d, _ := strconv.Atoi(fields[1])
+		added += a
+		deleted += d
+	}
+	return files, added, deleted, nil
+}
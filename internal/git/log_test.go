@@ -0,0 +1,30 @@
+package git
+
+import "testing"
+
+func TestParseLog(t *testing.T) {
+	out := "* " + recordSep + "bbb222" + fieldSep + "bbb" + fieldSep + "aaa111 ccc333" + fieldSep + "Alice" + fieldSep + "alice@example.com" + fieldSep + "1709280000" + fieldSep + "Merge branch 'x'\n" +
+		"|\\\n" +
+		"| * " + recordSep + "ccc333" + fieldSep + "ccc" + fieldSep + "aaa111" + fieldSep + "Bob" + fieldSep + "bob@example.com" + fieldSep + "1709270000" + fieldSep + "fix(ui): keep \x1f in subject\n" +
+		"|/\n" +
+		"* " + recordSep + "aaa111" + fieldSep + "aaa" + fieldSep + "" + fieldSep + "Alice" + fieldSep + "alice@example.com" + fieldSep + "1709260000" + fieldSep + "feat: first\n"
+
+	entries := parseLog(out)
+	if len(entries) != 5 {
+		t.Fatalf("expected 5 entries, got %d", len(entries))
+	}
+	if entries[1].Commit != nil || entries[1].Graph != "|\\" || entries[3].Commit != nil {
+		t.Errorf("expected graph-only lines without a commit: %+v, %+v", entries[1], entries[3])
+	}
+
+	merge := entries[0].Commit
+	if entries[0].Graph != "* " || merge.Hash != "bbb222" || len(merge.Parents) != 2 || merge.Date.Unix() != 1709280000 {
+		t.Errorf("unexpected merge commit: %q %+v", entries[0].Graph, merge)
+	}
+	if c := entries[2].Commit; entries[2].Graph != "| * " || c.Author != "Bob" || c.Subject != "fix(ui): keep \x1f in subject" {
+		t.Errorf("unexpected commit: %q %+v", entries[2].Graph, c)
+	}
+	if root := entries[4].Commit; len(root.Parents) != 0 || root.Subject != "feat: first" {
+		t.Errorf("unexpected root commit: %+v", root)
+	}
+}
@@ -0,0 +1,135 @@
+package git
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// RebaseState is the progress of a rebase that stopped (for an edit or a conflict).
+type RebaseState struct {
+	InProgress bool
+	Step       int      // Todo line being applied, 1-based
+	Total      int      // Lines in the todo list
+	Stopped    string   // Commit the rebase stopped at, if any
+	Conflicts  []string // Files with unresolved conflicts
+}
+
+// GetRebaseState reads the state of an interactive or merge-based rebase.
+func GetRebaseState() RebaseState {
+	dir, err := GitPath("rebase-merge")
+	if err != nil {
+		return RebaseState{}
+	}
+	if _, err := os.Stat(dir); err != nil {
+		return RebaseState{}
+	}
+	read := func(name string) string {
+		data, _ := os.ReadFile(filepath.Join(dir, name))
+		return strings.TrimSpace(string(data))
+	}
+	state := RebaseState{InProgress: true, Stopped: read("stopped-sha")}
+	state.Step, _ = strconv.Atoi(read("msgnum"))
+	state.Total, _ = strconv.Atoi(read("end"))
+	state.Conflicts = ConflictedFiles()
+	return state
+}
+
+// ConflictedFiles lists the files with unresolved merge conflicts.
+func ConflictedFiles() []string {
+	cmd := exec.Command("git", "diff", "--name-only", "--diff-filter=U")
+	out, err := cmd.Output()
+	if err != nil {
+		return nil
+	}
+	var files []string
+	for _, line := range strings.Split(string(out), "\n") {
+		if line != "" {
+			files = append(files, line)
+		}
+	}
+	return files
+}
+
+// EditTodo applies a rebase plan to the todo list git generated, changing
+// only the verbs and the order of the commits in both. plan holds
+// "<verb> <full hash> <subject>" lines, each optionally followed by exec
+// lines that run after it. Commits git left out (e.g. already upstream) stay
+// out, and lines the plan does not know (merges, comments) keep their place.
+func EditTodo(todo, plan string) string {
+	type step struct {
+		verb  string
+		hash  string
+		execs []string
+	}
+	var steps []*step
+	for _, line := range strings.Split(plan, "\n") {
+		fields := strings.Fields(line)
+		switch {
+		case len(fields) < 2:
+		case fields[0] == "exec":
+			if len(steps) > 0 {
+				last := steps[len(steps)-1]
+				last.execs = append(last.execs, line)
+			}
+		default:
+			steps = append(steps, &step{verb: fields[0], hash: fields[1]})
+		}
+	}
+	// find returns the step of an abbreviated hash from git's todo.
+	find := func(short string) *step {
+		for _, s := range steps {
+			if len(short) >= 4 && strings.HasPrefix(s.hash, short) {
+				return s
+			}
+		}
+		return nil
+	}
+
+	lines := strings.Split(strings.TrimSuffix(todo, "\n"), "\n")
+	rest := make(map[*step]string) // Git's line of each planned commit, without the verb
+	var slots []int
+	for i, line := range lines {
+		fields := strings.Fields(line)
+		if len(fields) < 2 || (fields[0] != "pick" && fields[0] != "p") {
+			continue
+		}
+		if s := find(fields[1]); s != nil {
+			_, rest[s], _ = strings.Cut(strings.TrimSpace(line), " ")
+			slots = append(slots, i)
+		}
+	}
+
+	// Fill the slots of the planned commits in the order of the plan.
+	var ordered []*step
+	for _, s := range steps {
+		if _, ok := rest[s]; ok {
+			ordered = append(ordered, s)
+		}
+	}
+	replaced := make(map[int]*step)
+	for k, i := range slots {
+		replaced[i] = ordered[k]
+	}
+
+	var b strings.Builder
+	for i, line := range lines {
+		s, ok := replaced[i]
+		if !ok {
+			b.WriteString(line + "\n")
+			continue
+		}
+		b.WriteString(s.verb + " " + rest[s] + "\n")
+		for _, e := range s.execs {
+			b.WriteString(e + "\n")
+		}
+	}
+	return b.String()
+}
+
+// ShellQuote quotes s for a POSIX shell.
+func ShellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
@@ -0,0 +1,48 @@
+package git
+
+import "testing"
+
+func TestEditTodo(t *testing.T) {
+	// git left out 3333333 (already upstream) and added a comment.
+	todo := "pick 1111111 feat: one\n" +
+		"pick 2222222 fix: two\n" +
+		"pick 4444444 docs: four\n" +
+		"\n" +
+		"# Rebase abc..def onto abc (3 commands)\n"
+	plan := "pick 4444444aaaa docs: four\n" +
+		"pick 3333333aaaa chore: three\n" +
+		"fixup 1111111aaaa feat: one\n" +
+		"pick 2222222aaaa fix: two\n" +
+		"exec git commit --amend --only --allow-empty --quiet --file '/tmp/msg'\n"
+
+	want := "pick 4444444 docs: four\n" +
+		"fixup 1111111 feat: one\n" +
+		"pick 2222222 fix: two\n" +
+		"exec git commit --amend --only --allow-empty --quiet --file '/tmp/msg'\n" +
+		"\n" +
+		"# Rebase abc..def onto abc (3 commands)\n"
+	if got := EditTodo(todo, plan); got != want {
+		t.Errorf("EditTodo() =\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestEditTodoKeepsUnknownLines(t *testing.T) {
+	todo := "pick 1111111 feat: one\n" +
+		"pick 5555555 from a merged branch\n" +
+		"pick 2222222 fix: two\n"
+	plan := "drop 2222222aaaa fix: two\n" +
+		"pick 1111111aaaa feat: one\n"
+
+	want := "drop 2222222 fix: two\n" +
+		"pick 5555555 from a merged branch\n" +
+		"pick 1111111 feat: one\n"
+	if got := EditTodo(todo, plan); got != want {
+		t.Errorf("EditTodo() =\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestShellQuote(t *testing.T) {
+	if got := ShellQuote("it's"); got != `'it'\''s'` {
+		t.Errorf("ShellQuote() = %s", got)
+	}
+}
@@ -0,0 +1,110 @@
+package git
+
+import (
+	"os/exec"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// HeadRef returns the branch HEAD points to ("refs/heads/main"), or "HEAD"
+// when it is detached.
+func HeadRef() string {
+	cmd := exec.Command("git", "symbolic-ref", "--quiet", "HEAD")
+	out, err := cmd.Output()
+	if err != nil {
+		return "HEAD"
+	}
+	return strings.TrimSpace(string(out))
+}
+
+// RevParse resolves a revision to a commit hash, or "" if it does not exist
+// (e.g. HEAD of an unborn branch).
+func RevParse(rev string) string {
+	cmd := exec.Command("git", "rev-parse", "--verify", "--quiet", rev+"^{commit}")
+	out, err := cmd.Output()
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(out))
+}
+
+// WriteTree stores the index as a tree object and returns its hash.
+// It fails while the index has unresolved conflicts.
+func WriteTree() (string, error) {
+	cmd := exec.Command("git", "write-tree")
+	out, err := cmd.Output()
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(string(out)), nil
+}
+
+// ReadTree replaces the index with a tree. The working tree is not touched.
+func ReadTree(tree string) error {
+	return run("read-tree", tree)
+}
+
+// UpdateRef points ref at newValue if it currently is oldValue ("" means
+// it must not exist yet), logging message in the reflog.
+func UpdateRef(ref, newValue, oldValue, message string) error {
+	return run("update-ref", "--no-deref", "-m", message, ref, newValue, oldValue)
+}
+
+// DeleteRef deletes ref if it currently is oldValue. Deleting the checked
+// out branch leaves it unborn, as before its first commit.
+func DeleteRef(ref, oldValue string) error {
+	return run("update-ref", "--no-deref", "-d", ref, oldValue)
+}
+
+// GitPath returns the path of a file inside the git directory, e.g. "raven/journal".
+func GitPath(name string) (string, error) {
+	cmd := exec.Command("git", "rev-parse", "--git-path", name)
+	out, err := cmd.Output()
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(string(out)), nil
+}
+
+// ReflogEntry is one move of a ref.
+type ReflogEntry struct {
+	Hash    string // Value of the ref after the move
+	Message string // e.g. "commit: feat: add x" or "reset: moving to HEAD~1"
+	Date    time.Time
+}
+
+// Reflog returns up to max entries of the reflog of ref, newest first.
+func Reflog(ref string, max int) ([]ReflogEntry, error) {
+	format := "--format=" + strings.Join([]string{"%H", "%ct", "%gs"}, fieldSep)
+	cmd := exec.Command("git", "reflog", "show", format, "-n", strconv.Itoa(max), ref, "--")
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, err
+	}
+	var entries []ReflogEntry
+	for _, line := range strings.Split(strings.TrimSpace(string(out)), "\n") {
+		fields := strings.SplitN(line, fieldSep, 3)
+		if len(fields) < 3 {
+			continue
+		}
+		unix, _ := strconv.ParseInt(fields[1], 10, 64)
+		entries = append(entries, ReflogEntry{Hash: fields[0], Message: fields[2], Date: time.Unix(unix, 0)})
+	}
+	return entries, nil
+}
+
+// Subject returns the subject line of a commit, or "" if it does not exist.
+func Subject(rev string) string {
+	cmd := exec.Command("git", "log", "-1", "--format=%s", rev, "--")
+	out, err := cmd.Output()
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(out))
+}
+
+// HasParent reports whether a commit has at least one parent.
+func HasParent(rev string) bool {
+	return RevParse(rev+"^") != ""
+}
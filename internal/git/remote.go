@@ -0,0 +1,117 @@
+package git
+
+import (
+	"fmt"
+	"os/exec"
+	"strings"
+)
+
+// BranchesContaining returns the local and remote-tracking branches (short
+// names, e.g. "main" and "origin/main") whose history contains rev.
+func BranchesContaining(rev string) (local, remote []string) {
+	cmd := exec.Command("git", "for-each-ref", "--contains", rev, "--format=%(refname)", "refs/heads", "refs/remotes")
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, nil
+	}
+	for _, ref := range strings.Fields(string(out)) {
+		switch {
+		case strings.HasSuffix(ref, "/HEAD"):
+			// origin/HEAD is a symbolic alias
+		case strings.HasPrefix(ref, "refs/heads/"):
+			local = append(local, strings.TrimPrefix(ref, "refs/heads/"))
+		case strings.HasPrefix(ref, "refs/remotes/"):
+			remote = append(remote, strings.TrimPrefix(ref, "refs/remotes/"))
+		}
+	}
+	return local, remote
+}
+
+// UpstreamBranch returns the remote and the remote branch the current branch
+// tracks, e.g. "origin" and "main", or "" if it has no upstream.
+func UpstreamBranch() (remote, branch string) {
+	current := CurrentBranch()
+	if current == "" {
+		return "", ""
+	}
+	remote = GetConfig("branch." + current + ".remote")
+	merge := GetConfig("branch." + current + ".merge")
+	if remote == "" || merge == "" {
+		return "", ""
+	}
+	return remote, strings.TrimPrefix(merge, "refs/heads/")
+}
+
+// PushForceWithLease replaces branch on remote with HEAD, but only if the
+// remote branch is still at expect (what we last fetched).
+func PushForceWithLease(remote, branch, expect string) error {
+	ref := "refs/heads/" + branch
+	return run("push", "--force-with-lease="+ref+":"+expect, remote, "HEAD:"+ref)
+}
+
+// Fetch downloads new commits from remote and prunes the remote-tracking
+// branches deleted there.
+func Fetch(remote string) error {
+	return run("fetch", "--prune", remote)
+}
+
+// AheadBehind counts the commits only on HEAD (ahead) and only on upstream
+// (behind), e.g. "origin/main".
+func AheadBehind(upstream string) (ahead, behind int, err error) {
+	cmd := exec.Command("git", "rev-list", "--left-right", "--count", "HEAD..."+upstream)
+	out, err := cmd.Output()
+	if err != nil {
+		return 0, 0, err
+	}
+	_, err = fmt.Sscan(string(out), &ahead, &behind)
+	return ahead, behind, err
+}
+
+// Push pushes HEAD to branch on remote. With setUpstream, the remote branch
+// becomes the upstream of the current branch.
+func Push(remote, branch string, setUpstream bool) error {
+	args := []string{"push"}
+	if setUpstream {
+		args = append(args, "--set-upstream")
+	}
+	return run(append(args, remote, "HEAD:refs/heads/"+branch)...)
+}
+
+// Integration is how integrating an upstream into the current branch ended.
+type Integration struct {
+	Output        string   // Combined output of git
+	Stopped       string   // "rebase" or "merge" when it stopped on a conflict
+	Conflicts     []string // Files with unresolved conflicts when it stopped
+	Stashed       bool     // Uncommitted changes were stashed and restored around it
+	StashConflict bool     // Restoring them conflicted; they are kept in the stash list
+}
+
+// Integrate brings the commits of upstream into the current branch by
+// rebasing onto it, or by merging it with merge. Uncommitted changes are
+// stashed around it. command builds the git command, e.g. to tag its reflog
+// entries. The error is set when git failed without stopping on a conflict.
+func Integrate(upstream string, merge bool, command func(args ...string) *exec.Cmd) (Integration, error) {
+	cmd := command("rebase", "--autostash", upstream)
+	if merge {
+		cmd = command("merge", "--autostash", "--no-edit", upstream)
+	}
+	out, err := cmd.CombinedOutput()
+
+	result := Integration{Output: string(out)}
+	switch {
+	case GetRebaseState().InProgress:
+		result.Stopped = "rebase"
+	case RevParse("MERGE_HEAD") != "":
+		result.Stopped = "merge"
+	}
+	if result.Stopped != "" {
+		result.Conflicts = ConflictedFiles()
+		return result, nil
+	}
+	if err != nil {
+		return result, err
+	}
+	result.StashConflict = strings.Contains(result.Output, "resulted in conflicts")
+	result.Stashed = !result.StashConflict && strings.Contains(result.Output, "Created autostash")
+	return result, nil
+}
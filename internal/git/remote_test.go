@@ -0,0 +1,193 @@
+package git
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// gitIn returns a function running git in dir, failing the test on error.
+func gitIn(t *testing.T, dir string) func(args ...string) string {
+	return func(args ...string) string {
+		t.Helper()
+		cmd := exec.Command("git", args...)
+		cmd.Dir = dir
+		out, err := cmd.CombinedOutput()
+		if err != nil {
+			t.Fatalf("git %v: %v\n%s", args, err, out)
+		}
+		return strings.TrimSpace(string(out))
+	}
+}
+
+func TestFetchAheadBehindAndPush(t *testing.T) {
+	other, run := syncRepos(t)
+	pushIn(t, other, "b.txt", "b\n", "feat: second")
+	commitIn(t, ".", "c.txt", "c\n", "feat: ours")
+
+	if err := Fetch("origin"); err != nil {
+		t.Fatal(err)
+	}
+	ahead, behind, err := AheadBehind("origin/main")
+	if err != nil || ahead != 1 || behind != 1 {
+		t.Fatalf("AheadBehind() = %d, %d, %v; want 1, 1 (diverged)", ahead, behind, err)
+	}
+
+	// A diverged branch is rejected; after a rebase it goes through.
+	if err := Push("origin", "main", false); err == nil {
+		t.Fatal("expected pushing a diverged branch to fail")
+	}
+	run("rebase", "-q", "origin/main")
+	if err := Push("origin", "main", false); err != nil {
+		t.Fatal(err)
+	}
+	run("fetch", "-q", "origin")
+	if ahead, behind, _ := AheadBehind("origin/main"); ahead != 0 || behind != 0 {
+		t.Errorf("after push AheadBehind() = %d, %d; want 0, 0", ahead, behind)
+	}
+}
+
+// syncRepos sets up a bare remote with one commit and two clones of it:
+// other pushes to it, ours (the current directory) syncs with it. It returns
+// the other clone's directory and a git runner for ours.
+func syncRepos(t *testing.T) (other string, ours func(args ...string) string) {
+	t.Helper()
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not installed")
+	}
+	t.Setenv("GIT_CONFIG_GLOBAL", "/dev/null")
+	t.Setenv("GIT_AUTHOR_NAME", "Alice")
+	t.Setenv("GIT_AUTHOR_EMAIL", "alice@example.com")
+	t.Setenv("GIT_COMMITTER_NAME", "Alice")
+	t.Setenv("GIT_COMMITTER_EMAIL", "alice@example.com")
+
+	root := t.TempDir()
+	bare := filepath.Join(root, "origin.git")
+	gitIn(t, root)("init", "-q", "--bare", "-b", "main", bare)
+	other, oursDir := filepath.Join(root, "other"), filepath.Join(root, "ours")
+	gitIn(t, root)("clone", "-q", bare, other)
+	commitIn(t, other, "a.txt", "a\n", "feat: first")
+	gitIn(t, other)("push", "-q", "origin", "HEAD:main")
+	gitIn(t, root)("clone", "-q", bare, oursDir)
+
+	t.Chdir(oursDir)
+	return other, gitIn(t, oursDir)
+}
+
+// commitIn writes a file in the repository at dir and commits it.
+func commitIn(t *testing.T, dir, path, content, message string) {
+	t.Helper()
+	os.WriteFile(filepath.Join(dir, path), []byte(content), 0o644)
+	gitIn(t, dir)("add", ".")
+	gitIn(t, dir)("commit", "-q", "-m", message)
+}
+
+// pushIn commits a file in the repository at dir and pushes it to origin/main.
+func pushIn(t *testing.T, dir, path, content, message string) {
+	t.Helper()
+	commitIn(t, dir, path, content, message)
+	gitIn(t, dir)("push", "-q", "origin", "HEAD:main")
+}
+
+// plainGit builds git commands without reflog tagging.
+func plainGit(args ...string) *exec.Cmd {
+	return exec.Command("git", args...)
+}
+
+func TestIntegrate(t *testing.T) {
+	t.Run("up to date", func(t *testing.T) {
+		_, ours := syncRepos(t)
+		head := ours("rev-parse", "HEAD")
+		result, err := Integrate("origin/main", false, plainGit)
+		if err != nil || result.Stopped != "" || ours("rev-parse", "HEAD") != head {
+			t.Errorf("Integrate() = %+v, %v; want no change", result, err)
+		}
+	})
+
+	t.Run("fast-forward", func(t *testing.T) {
+		other, ours := syncRepos(t)
+		pushIn(t, other, "b.txt", "b\n", "feat: second")
+		ours("fetch", "-q", "origin")
+		os.WriteFile("a.txt", []byte("a\nlocal\n"), 0o644) // Uncommitted, restored afterwards
+
+		result, err := Integrate("origin/main", false, plainGit)
+		if err != nil || result.Stopped != "" {
+			t.Fatalf("Integrate() = %+v, %v", result, err)
+		}
+		if ours("rev-parse", "HEAD") != ours("rev-parse", "origin/main") {
+			t.Error("expected HEAD to be fast-forwarded to origin/main")
+		}
+		if !result.Stashed || ours("status", "--porcelain") != "M a.txt" {
+			t.Errorf("expected the uncommitted change to be stashed and restored: %+v", result)
+		}
+	})
+
+	t.Run("diverged rebase", func(t *testing.T) {
+		other, ours := syncRepos(t)
+		pushIn(t, other, "b.txt", "b\n", "feat: theirs")
+		commitIn(t, ".", "c.txt", "c\n", "feat: ours")
+		ours("fetch", "-q", "origin")
+
+		result, err := Integrate("origin/main", false, plainGit)
+		if err != nil || result.Stopped != "" {
+			t.Fatalf("Integrate() = %+v, %v", result, err)
+		}
+		if ahead, behind, _ := AheadBehind("origin/main"); ahead != 1 || behind != 0 {
+			t.Errorf("AheadBehind() = %d, %d; want 1, 0", ahead, behind)
+		}
+		if ours("rev-parse", "HEAD^") != ours("rev-parse", "origin/main") || ours("log", "-1", "--format=%s") != "feat: ours" {
+			t.Error("expected our commit replayed on top of origin/main")
+		}
+	})
+
+	t.Run("diverged merge", func(t *testing.T) {
+		other, ours := syncRepos(t)
+		pushIn(t, other, "b.txt", "b\n", "feat: theirs")
+		commitIn(t, ".", "c.txt", "c\n", "feat: ours")
+		ours("fetch", "-q", "origin")
+
+		result, err := Integrate("origin/main", true, plainGit)
+		if err != nil || result.Stopped != "" {
+			t.Fatalf("Integrate() = %+v, %v", result, err)
+		}
+		if parents := strings.Fields(ours("log", "-1", "--format=%P")); len(parents) != 2 {
+			t.Errorf("expected a merge commit, got parents %v", parents)
+		}
+	})
+
+	for _, merge := range []bool{false, true} {
+		want := map[bool]string{false: "rebase", true: "merge"}[merge]
+		t.Run(want+" conflict", func(t *testing.T) {
+			other, ours := syncRepos(t)
+			pushIn(t, other, "a.txt", "theirs\n", "fix: theirs")
+			commitIn(t, ".", "a.txt", "ours\n", "fix: ours")
+			ours("fetch", "-q", "origin")
+
+			result, err := Integrate("origin/main", merge, plainGit)
+			if err != nil || result.Stopped != want || len(result.Conflicts) != 1 || result.Conflicts[0] != "a.txt" {
+				t.Errorf("Integrate() = %+v, %v; want stopped on a %s conflict in a.txt", result, err, want)
+			}
+		})
+	}
+}
+
+func TestPushSetsUpstream(t *testing.T) {
+	_, run := syncRepos(t)
+
+	// A new branch gets its upstream on first push.
+	run("checkout", "-q", "-b", "feat/x")
+	if got := Upstream(); got != "" {
+		t.Fatalf("Upstream() = %q before pushing, want none", got)
+	}
+	if err := Push("origin", "feat/x", true); err != nil {
+		t.Fatal(err)
+	}
+	if got := Upstream(); got != "origin/feat/x" {
+		t.Errorf("Upstream() = %q, want origin/feat/x", got)
+	}
+	if remote, branch := UpstreamBranch(); remote != "origin" || branch != "feat/x" {
+		t.Errorf("UpstreamBranch() = %q, %q; want origin, feat/x", remote, branch)
+	}
+}
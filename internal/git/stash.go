@@ -0,0 +1,102 @@
+package git
+
+import (
+	"os/exec"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Stash is an entry of the stash list.
+type Stash struct {
+	Ref     string // "stash@{0}"
+	Branch  string // Branch the stash was made on
+	Message string
+	Date    time.Time
+}
+
+// GetStashes lists the stashes, newest first.
+func GetStashes() ([]Stash, error) {
+	format := "--format=" + strings.Join([]string{"%gd", "%ct", "%gs"}, fieldSep)
+	cmd := exec.Command("git", "stash", "list", format)
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, err
+	}
+	var stashes []Stash
+	for _, line := range strings.Split(strings.TrimSpace(string(out)), "\n") {
+		fields := strings.SplitN(line, fieldSep, 3)
+		if len(fields) < 3 {
+			continue
+		}
+		unix, _ := strconv.ParseInt(fields[1], 10, 64)
+		branch, message := parseStashSubject(fields[2])
+		stashes = append(stashes, Stash{Ref: fields[0], Branch: branch, Message: message, Date: time.Unix(unix, 0)})
+	}
+	return stashes, nil
+}
+
+// parseStashSubject splits a stash reflog subject, "On main: message" or
+// "WIP on main: abc1234 subject", into the branch and the message.
+func parseStashSubject(subject string) (branch, message string) {
+	rest := strings.TrimPrefix(subject, "WIP ")
+	rest, ok := strings.CutPrefix(rest, "On ")
+	if !ok {
+		rest, ok = strings.CutPrefix(rest, "on ")
+	}
+	if !ok {
+		return "", subject
+	}
+	branch, message, _ = strings.Cut(rest, ": ")
+	return branch, message
+}
+
+// ShowStash returns the stat and patch of a stash, untracked files included.
+func ShowStash(ref string) (string, error) {
+	cmd := exec.Command("git", "stash", "show", "--include-untracked", "--stat", "--patch", ref)
+	out, err := cmd.Output()
+	if err != nil {
+		return "", err
+	}
+	return string(out), nil
+}
+
+// GetWorkingDiff returns the staged and unstaged changes to tracked files.
+func GetWorkingDiff() (string, error) {
+	cmd := exec.Command("git", "diff", "HEAD")
+	out, err := cmd.Output()
+	if err != nil {
+		return "", err
+	}
+	return string(out), nil
+}
+
+// StashPush stashes the local changes with a message, untracked files too if asked.
+func StashPush(message string, untracked bool) error {
+	args := []string{"stash", "push", "--message", message}
+	if untracked {
+		args = append(args, "--include-untracked")
+	}
+	return run(args...)
+}
+
+// StashApply applies a stash, keeping it in the list.
+func StashApply(ref string) error {
+	return run("stash", "apply", ref)
+}
+
+// StashPop applies a stash and removes it from the list.
+func StashPop(ref string) error {
+	return run("stash", "pop", ref)
+}
+
+// StashDrop removes a stash from the list.
+func StashDrop(ref string) error {
+	return run("stash", "drop", ref)
+}
+
+// StashBranch creates a branch at the commit the stash was made on, applies
+// the stash there and drops it.
+func StashBranch(name, ref string) error {
+	return run("stash", "branch", name, ref)
+}
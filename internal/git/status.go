@@ -2,6 +2,7 @@ package git
 
 import (
 	"os/exec"
+	"strconv"
 	"strings"
 )
 
@@ -70,6 +71,21 @@ func GetStatus() (StatusResult, error) {
 	return result, nil
 }
 
+// Branch returns the branch name from the status header, or "" on a detached HEAD.
+func (s StatusResult) Branch() string {
+	info := s.BranchInfo
+	// "No commits yet on main" (or "Initial commit on main" in older git)
+	if i := strings.LastIndex(info, " on "); i >= 0 && !strings.Contains(info, "...") {
+		info = info[i+len(" on "):]
+	}
+	if strings.HasPrefix(info, "HEAD (no branch)") {
+		return ""
+	}
+	name, _, _ := strings.Cut(info, "...")
+	name, _, _ = strings.Cut(name, " ")
+	return name
+}
+
 // StageFile stages a specific file (git add).
 func StageFile(path string) error {
 	cmd := exec.Command("git", "add", path)
@@ -81,3 +97,36 @@ func UnstageFile(path string) error {
 	cmd := exec.Command("git", "restore", "--staged", path)
 	return cmd.Run()
 }
+
+// StagedFiles lists the files whose staged content differs from HEAD, leaving
+// out deletions.
+func StagedFiles() ([]string, error) {
+	cmd := exec.Command("git", "diff", "--cached", "--name-only", "--diff-filter=ACMR", "-z")
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, err
+	}
+	var files []string
+	for _, f := range strings.Split(string(out), "\x00") {
+		if f != "" {
+			files = append(files, f)
+		}
+	}
+	return files, nil
+}
+
+// StagedSize returns the size in bytes of a file's staged content.
+func StagedSize(path string) (int64, error) {
+	cmd := exec.Command("git", "cat-file", "-s", ":"+path)
+	out, err := cmd.Output()
+	if err != nil {
+		return 0, err
+	}
+	return strconv.ParseInt(strings.TrimSpace(string(out)), 10, 64)
+}
+
+// StagedContent returns a file's staged content, which may differ from the
+// working tree.
+func StagedContent(path string) ([]byte, error) {
+	return exec.Command("git", "cat-file", "blob", ":"+path).Output()
+}
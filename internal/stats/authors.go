@@ -0,0 +1,74 @@
+package stats
+
+import (
+	"sort"
+	"strings"
+	"time"
+)
+
+// AuthorStats aggregates the contributions of one author.
+type AuthorStats struct {
+	Name      string
+	Email     string
+	Commits   int
+	Additions int
+	Deletions int
+}
+
+// Filter returns the contributions for which keep returns true.
+func (a Activity) Filter(keep func(Contribution) bool) Activity {
+	filtered := make(Activity)
+	for day, contributions := range a {
+		for _, c := range contributions {
+			if keep(c) {
+				filtered[day] = append(filtered[day], c)
+			}
+		}
+	}
+	return filtered
+}
+
+// FilterAuthor keeps contributions whose author name or email contains pattern (case-insensitive).
+func (a Activity) FilterAuthor(pattern string) Activity {
+	pattern = strings.ToLower(pattern)
+	return a.Filter(func(c Contribution) bool {
+		return strings.Contains(strings.ToLower(c.Author), pattern) ||
+			strings.Contains(strings.ToLower(c.Email), pattern)
+	})
+}
+
+// FilterEmail keeps contributions by exactly this author email (case-insensitive).
+func (a Activity) FilterEmail(email string) Activity {
+	return a.Filter(func(c Contribution) bool {
+		return strings.EqualFold(c.Email, email)
+	})
+}
+
+// Leaderboard ranks authors by commits between start and end (inclusive days).
+// Zero bounds are open-ended. Authors are identified by email.
+func (a Activity) Leaderboard(start, end time.Time) []AuthorStats {
+	byEmail := make(map[string]*AuthorStats)
+	a.each(start, end, func(_ time.Time, c Contribution) {
+		key := strings.ToLower(c.Email)
+		s, ok := byEmail[key]
+		if !ok {
+			s = &AuthorStats{Name: c.Author, Email: c.Email}
+			byEmail[key] = s
+		}
+		s.Commits += c.Commits
+		s.Additions += c.Additions
+		s.Deletions += c.Deletions
+	})
+
+	board := make([]AuthorStats, 0, len(byEmail))
+	for _, s := range byEmail {
+		board = append(board, *s)
+	}
+	sort.Slice(board, func(i, j int) bool {
+		if board[i].Commits != board[j].Commits {
+			return board[i].Commits > board[j].Commits
+		}
+		return board[i].Name < board[j].Name
+	})
+	return board
+}
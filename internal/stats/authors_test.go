@@ -0,0 +1,39 @@
+package stats
+
+import (
+	"testing"
+	"time"
+)
+
+func TestLeaderboardAndFilters(t *testing.T) {
+	activity := Activity{
+		"2024-03-01": {
+			{Author: "Alice", Email: "alice@example.com", Commits: 1, Additions: 10, Deletions: 2},
+			{Author: "Bob", Email: "bob@example.com", Commits: 1, Additions: 1},
+		},
+		"2024-03-02": {
+			{Author: "Alice", Email: "Alice@Example.com", Commits: 1, Additions: 5, Deletions: 5},
+		},
+		"2024-04-01": {
+			{Author: "Bob", Email: "bob@example.com", Commits: 1, Additions: 100},
+		},
+	}
+
+	board := activity.Leaderboard(time.Time{}, time.Date(2024, 3, 31, 0, 0, 0, 0, time.Local))
+	if len(board) != 2 {
+		t.Fatalf("expected 2 authors, got %d", len(board))
+	}
+	if board[0].Name != "Alice" || board[0].Commits != 2 || board[0].Additions != 15 || board[0].Deletions != 7 {
+		t.Errorf("unexpected leader: %+v", board[0])
+	}
+	if board[1].Name != "Bob" || board[1].Commits != 1 {
+		t.Errorf("expected April commit to be outside the range: %+v", board[1])
+	}
+
+	if got := activity.FilterEmail("alice@example.com"); got.Count("2024-03-01") != 1 || got.Count("2024-03-02") != 1 {
+		t.Errorf("FilterEmail should match case-insensitively: %v", got)
+	}
+	if got := activity.FilterAuthor("bo"); got.Count("2024-04-01") != 1 || got.Count("2024-03-02") != 0 {
+		t.Errorf("FilterAuthor should match name substrings: %v", got)
+	}
+}
@@ -0,0 +1,145 @@
+package stats
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// cacheVersion is bumped whenever the cached Contribution format changes.
+const cacheVersion = 3
+
+// cacheFile is the on-disk format of .git/raven/stats-cache.json. It holds the
+// per-day contributions only, never the commits themselves.
+type cacheFile struct {
+	Version  int      `json:"version"`
+	Head     string   `json:"head"`     // Last processed commit
+	Mailmap  string   `json:"mailmap"`  // Hash of .mailmap, identities change with it
+	Settings string   `json:"settings"` // Bucketing and language rules the days were built with
+	Activity Activity `json:"activity"`
+}
+
+// LoadActivity is GetCommitCountsIn backed by a cache under .git/raven/.
+// Only commits added since the cached HEAD are read from git. The cache is
+// rebuilt when the cached HEAD is no longer an ancestor of HEAD (rebase,
+// reset, amend, branch switch), when .mailmap changes or when opts differ
+// from the ones it was built with.
+func LoadActivity(dir string, opts Options) (Activity, error) {
+	head, err := gitOutput(dir, "rev-parse", "--verify", "-q", "HEAD")
+	if err != nil {
+		// No commits yet (or not a repository): nothing to cache
+		return GetCommitCountsIn(dir, opts)
+	}
+	gitDir, err := gitOutput(dir, "rev-parse", "--absolute-git-dir")
+	if err != nil {
+		return GetCommitCountsIn(dir, opts)
+	}
+	path := filepath.Join(gitDir, "raven", "stats-cache.json")
+	mailmap := mailmapHash(dir)
+	settings := opts.Bucketing.key() + " " + opts.languages().fingerprint()
+
+	cache, ok := readCache(path)
+	if ok && (cache.Version != cacheVersion || cache.Mailmap != mailmap || cache.Settings != settings) {
+		ok = false
+	}
+
+	var activity Activity
+	switch {
+	case ok && cache.Head == head:
+		activity = cache.Activity
+		activity.tagRepo(dir)
+
+	case ok && isAncestor(dir, cache.Head, head):
+		// Fast-forward: only read the new commits
+		added, err := logActivity(dir, opts, cache.Head+".."+head)
+		if err != nil {
+			return nil, err
+		}
+		activity = cache.Activity
+		activity.tagRepo(dir)
+		activity.Merge(added)
+
+	default:
+		// History was rewritten (or no cache yet): rebuild
+		activity, err = logActivity(dir, opts)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if !ok || cache.Head != head {
+		// Best effort: a read-only .git just means no caching
+		_ = writeCache(path, cacheFile{Version: cacheVersion, Head: head, Mailmap: mailmap, Settings: settings, Activity: activity})
+	}
+	return activity, nil
+}
+
+func readCache(path string) (cacheFile, bool) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return cacheFile{}, false
+	}
+	var cache cacheFile
+	if err := json.Unmarshal(data, &cache); err != nil || cache.Activity == nil {
+		return cacheFile{}, false
+	}
+	return cache, true
+}
+
+// writeCache writes atomically so a concurrent raven never reads a partial file.
+// Repo is not stored: the same repository may be reached through different paths.
+func writeCache(path string, cache cacheFile) error {
+	data, err := json.Marshal(cache)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(path), "stats-cache-*.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+func mailmapHash(dir string) string {
+	top, err := gitOutput(dir, "rev-parse", "--show-toplevel")
+	if err != nil {
+		return ""
+	}
+	data, err := os.ReadFile(filepath.Join(top, ".mailmap"))
+	if err != nil {
+		return ""
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+func isAncestor(dir, ancestor, rev string) bool {
+	cmd := exec.Command("git", "merge-base", "--is-ancestor", ancestor, rev)
+	cmd.Dir = dir
+	return cmd.Run() == nil
+}
+
+func gitOutput(dir string, args ...string) (string, error) {
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	out, err := cmd.Output()
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(string(out)), nil
+}
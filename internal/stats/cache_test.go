@@ -0,0 +1,113 @@
+package stats
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// gitRepo creates a throwaway repository and returns a helper to run git in it.
+func gitRepo(t *testing.T) (string, func(args ...string)) {
+	t.Helper()
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not installed")
+	}
+	dir := t.TempDir()
+	run := func(args ...string) {
+		t.Helper()
+		cmd := exec.Command("git", args...)
+		cmd.Dir = dir
+		cmd.Env = append(os.Environ(),
+			"GIT_AUTHOR_NAME=Alice", "GIT_AUTHOR_EMAIL=alice@example.com",
+			"GIT_COMMITTER_NAME=Alice", "GIT_COMMITTER_EMAIL=alice@example.com",
+			"GIT_CONFIG_GLOBAL=/dev/null")
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %v\n%s", args, err, out)
+		}
+	}
+	run("init", "-q")
+	return dir, run
+}
+
+func commitFile(t *testing.T, dir string, run func(...string), name, msg string) {
+	t.Helper()
+	if err := os.WriteFile(filepath.Join(dir, name), []byte(msg+"\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	run("add", name)
+	run("commit", "-q", "-m", msg)
+}
+
+func totalCommits(a Activity) int {
+	n := 0
+	for day := range a {
+		n += a.Count(day)
+	}
+	return n
+}
+
+func TestLoadActivityCache(t *testing.T) {
+	dir, run := gitRepo(t)
+	commitFile(t, dir, run, "a.txt", "feat: first")
+	commitFile(t, dir, run, "b.txt", "feat: second")
+
+	activity, err := LoadActivity(dir, Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if totalCommits(activity) != 2 {
+		t.Fatalf("expected 2 commits, got %d", totalCommits(activity))
+	}
+	cachePath := filepath.Join(dir, ".git", "raven", "stats-cache.json")
+	data, err := os.ReadFile(cachePath)
+	if err != nil {
+		t.Fatalf("expected cache file: %v", err)
+	}
+	if strings.Contains(string(data), "feat: first") || strings.Contains(string(data), "a.txt") {
+		t.Errorf("expected only per-day totals in the cache, got %s", data)
+	}
+
+	// Other settings rebuild instead of reusing days bucketed differently
+	utc, err := LoadActivity(dir, Options{Bucketing: Bucketing{Committer: true, Location: time.UTC}})
+	if err != nil || totalCommits(utc) != 2 {
+		t.Fatalf("expected 2 commits with other settings, got %d (%v)", totalCommits(utc), err)
+	}
+
+	// Fast-forward: new commit is merged into the cache
+	commitFile(t, dir, run, "c.txt", "feat: third")
+	activity, err = LoadActivity(dir, Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if totalCommits(activity) != 3 {
+		t.Fatalf("expected 3 commits after incremental update, got %d", totalCommits(activity))
+	}
+	for _, contributions := range activity {
+		for _, c := range contributions {
+			if c.Repo != dir {
+				t.Errorf("expected contributions tagged with %s, got %q", dir, c.Repo)
+			}
+		}
+	}
+
+	// Rewrite: dropping two commits must not keep them in the stats
+	run("reset", "-q", "--hard", "HEAD~2")
+	commitFile(t, dir, run, "d.txt", "fix: rewritten")
+	activity, err = LoadActivity(dir, Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if totalCommits(activity) != 2 {
+		t.Fatalf("expected 2 commits after history rewrite, got %d", totalCommits(activity))
+	}
+	uncached, err := GetCommitCountsIn(dir, Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if totalCommits(uncached) != totalCommits(activity) {
+		t.Errorf("cached and uncached results differ: %d vs %d", totalCommits(activity), totalCommits(uncached))
+	}
+}
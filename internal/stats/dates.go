@@ -0,0 +1,104 @@
+package stats
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Calendar days (activity keys, ranges, grid positions) are represented as
+// midnight UTC. UTC has no DST transitions, so every day is exactly 24 hours
+// long and day arithmetic never skips or repeats a date.
+
+// Date returns the calendar day of t (in t's own location) as midnight UTC.
+func Date(t time.Time) time.Time {
+	y, m, d := t.Date()
+	return time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
+}
+
+// ParseDay parses an activity key (YYYY-MM-DD).
+func ParseDay(key string) (time.Time, error) {
+	return time.Parse("2006-01-02", key)
+}
+
+// StartOfWeek returns the first day of the week containing d, for weeks
+// starting on first.
+func StartOfWeek(d time.Time, first time.Weekday) time.Time {
+	d = Date(d)
+	return d.AddDate(0, 0, -WeekdayIndex(d.Weekday(), first))
+}
+
+// WeekdayIndex returns the position of wd in a week starting on first (0-6).
+func WeekdayIndex(wd, first time.Weekday) int {
+	return (int(wd) - int(first) + 7) % 7
+}
+
+// ParseWeekday parses a weekday name such as "monday" or "Mon".
+func ParseWeekday(name string) (time.Weekday, error) {
+	name = strings.ToLower(name)
+	for wd := time.Sunday; wd <= time.Saturday; wd++ {
+		full := strings.ToLower(wd.String())
+		if name == full || (len(name) >= 3 && strings.HasPrefix(full, name)) {
+			return wd, nil
+		}
+	}
+	return time.Sunday, fmt.Errorf("unknown weekday %q", name)
+}
+
+// Bucketing decides which calendar day a commit belongs to.
+type Bucketing struct {
+	Committer bool           // Use the committer date instead of the author date
+	Location  *time.Location // Zone of the day boundaries, nil for each commit's own UTC offset
+}
+
+// ParseTimezone parses a bucketing zone: "local", "original" (each commit's
+// own offset, returned as nil), "UTC" or an IANA name such as "Europe/Berlin".
+func ParseTimezone(name string) (*time.Location, error) {
+	switch strings.ToLower(name) {
+	case "", "local":
+		return time.Local, nil
+	case "original":
+		return nil, nil
+	case "utc":
+		return time.UTC, nil
+	}
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		return nil, fmt.Errorf("unknown time zone %q", name)
+	}
+	return loc, nil
+}
+
+// Time returns the commit's bucketing time: the author or committer date,
+// converted to the bucketing zone.
+func (b Bucketing) Time(c Commit) time.Time {
+	t := c.AuthorTime
+	if b.Committer {
+		t = c.CommitTime
+	}
+	if b.Location != nil {
+		t = t.In(b.Location)
+	}
+	return t
+}
+
+// Today returns the current calendar day in the bucketing zone.
+func (b Bucketing) Today() time.Time {
+	now := time.Now()
+	if b.Location != nil {
+		now = now.In(b.Location)
+	}
+	return Date(now)
+}
+
+// key identifies the bucketing in the stats cache.
+func (b Bucketing) key() string {
+	zone := "original"
+	if b.Location != nil {
+		zone = b.Location.String()
+	}
+	if b.Committer {
+		return "committer/" + zone
+	}
+	return "author/" + zone
+}
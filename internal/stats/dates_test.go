@@ -0,0 +1,143 @@
+package stats
+
+import (
+	"testing"
+	"time"
+)
+
+func mustParse(t *testing.T, s string) time.Time {
+	t.Helper()
+	v, err := time.Parse(time.RFC3339, s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return v
+}
+
+func loadZone(t *testing.T, name string) *time.Location {
+	t.Helper()
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		t.Skipf("time zone data unavailable: %v", err)
+	}
+	return loc
+}
+
+func TestBucketing(t *testing.T) {
+	// Authored late in New York, committed the next morning in Berlin
+	c := Commit{
+		Hash:       "a",
+		AuthorTime: mustParse(t, "2024-03-01T23:30:00-05:00"),
+		CommitTime: mustParse(t, "2024-03-02T09:00:00+01:00"),
+	}
+
+	cases := []struct {
+		name string
+		b    Bucketing
+		day  string
+		hour int
+	}{
+		{"author offset", Bucketing{}, "2024-03-01", 23},
+		{"utc", Bucketing{Location: time.UTC}, "2024-03-02", 4},
+		{"committer offset", Bucketing{Committer: true}, "2024-03-02", 9},
+		{"committer in utc", Bucketing{Committer: true, Location: time.UTC}, "2024-03-02", 8},
+	}
+	for _, tc := range cases {
+		got := aggregate("", []Commit{c}, Options{Bucketing: tc.b})
+		if got.Count(tc.day) != 1 {
+			t.Errorf("%s: expected commit on %s, got %v", tc.name, tc.day, got)
+			continue
+		}
+		if h := got[tc.day][0].Hours[tc.hour]; h != 1 {
+			t.Errorf("%s: expected the commit at hour %d, got %v", tc.name, tc.hour, got[tc.day][0].Hours)
+		}
+	}
+}
+
+func TestBucketingAcrossDST(t *testing.T) {
+	// Santiago skips from 00:00 to 01:00 on 2024-09-08: local midnight does not exist
+	santiago := loadZone(t, "America/Santiago")
+	commits := []Commit{
+		{Hash: "before", AuthorTime: mustParse(t, "2024-09-08T03:59:00Z")}, // 23:59 on the 7th (-04)
+		{Hash: "after", AuthorTime: mustParse(t, "2024-09-08T04:00:00Z")},  // 01:00 on the 8th (-03)
+	}
+	got := aggregate("", commits, Options{Bucketing: Bucketing{Location: santiago}})
+	if got.Count("2024-09-07") != 1 || got["2024-09-07"][0].Hours[23] != 1 {
+		t.Errorf("expected the commit before the transition on the 7th, got %v", got)
+	}
+	if got.Count("2024-09-08") != 1 || got["2024-09-08"][0].Hours[1] != 1 {
+		t.Errorf("expected the commit after the transition on the 8th, got %v", got)
+	}
+
+	// New York falls back on 2024-11-03: the 25-hour day is still one day
+	newYork := loadZone(t, "America/New_York")
+	commits = []Commit{
+		{Hash: "early", AuthorTime: mustParse(t, "2024-11-03T04:30:00Z")}, // 00:30 EDT
+		{Hash: "late", AuthorTime: mustParse(t, "2024-11-04T04:30:00Z")},  // 23:30 EST
+	}
+	if got := aggregate("", commits, Options{Bucketing: Bucketing{Location: newYork}}); got.Count("2024-11-03") != 2 {
+		t.Errorf("expected both commits on 2024-11-03, got %v", got)
+	}
+}
+
+func TestDaysAcrossDST(t *testing.T) {
+	santiago := loadZone(t, "America/Santiago")
+	start := time.Date(2024, 9, 6, 0, 0, 0, 0, santiago)
+	end := time.Date(2024, 9, 10, 0, 0, 0, 0, santiago)
+
+	var got []string
+	for _, d := range Days(start, end) {
+		got = append(got, d.Format("2006-01-02"))
+	}
+	want := []string{"2024-09-06", "2024-09-07", "2024-09-08", "2024-09-09", "2024-09-10"}
+	if len(got) != len(want) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("day %d = %s, want %s", i, got[i], want[i])
+		}
+	}
+}
+
+func TestStartOfWeek(t *testing.T) {
+	wed := time.Date(2024, 3, 13, 15, 0, 0, 0, time.UTC)
+	if got := StartOfWeek(wed, time.Sunday); got.Format("2006-01-02") != "2024-03-10" {
+		t.Errorf("Sunday week start = %s", got.Format("2006-01-02"))
+	}
+	if got := StartOfWeek(wed, time.Monday); got.Format("2006-01-02") != "2024-03-11" {
+		t.Errorf("Monday week start = %s", got.Format("2006-01-02"))
+	}
+	sun := time.Date(2024, 3, 17, 0, 0, 0, 0, time.UTC)
+	if got := StartOfWeek(sun, time.Monday); got.Format("2006-01-02") != "2024-03-11" {
+		t.Errorf("Sunday should end a Monday week, got %s", got.Format("2006-01-02"))
+	}
+	if WeekdayIndex(time.Sunday, time.Monday) != 6 || WeekdayIndex(time.Monday, time.Monday) != 0 {
+		t.Errorf("unexpected weekday index")
+	}
+}
+
+func TestParseDateSettings(t *testing.T) {
+	if wd, err := ParseWeekday("Mon"); err != nil || wd != time.Monday {
+		t.Errorf("ParseWeekday(Mon) = %v, %v", wd, err)
+	}
+	if wd, err := ParseWeekday("saturday"); err != nil || wd != time.Saturday {
+		t.Errorf("ParseWeekday(saturday) = %v, %v", wd, err)
+	}
+	if _, err := ParseWeekday("m"); err == nil {
+		t.Errorf("expected ambiguous weekday to fail")
+	}
+
+	if loc, err := ParseTimezone("original"); err != nil || loc != nil {
+		t.Errorf("ParseTimezone(original) = %v, %v", loc, err)
+	}
+	if loc, err := ParseTimezone("local"); err != nil || loc != time.Local {
+		t.Errorf("ParseTimezone(local) = %v, %v", loc, err)
+	}
+	if loc, err := ParseTimezone("UTC"); err != nil || loc != time.UTC {
+		t.Errorf("ParseTimezone(UTC) = %v, %v", loc, err)
+	}
+	if _, err := ParseTimezone("Mars/Olympus"); err == nil {
+		t.Errorf("expected unknown zone to fail")
+	}
+}
@@ -0,0 +1,300 @@
+package stats
+
+import (
+	"bufio"
+	"crypto/sha256"
+	"encoding/hex"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// OtherLanguage is reported for files no rule matches.
+const OtherLanguage = "Other"
+
+// Languages classifies changed files by extension, file name and shebang.
+// It caches shebang lookups and is not safe for concurrent use.
+type Languages struct {
+	Extensions   map[string]string // ".go" -> "Go" (lowercase)
+	Filenames    map[string]string // "Makefile" -> "Makefile"
+	Interpreters map[string]string // "python3" -> "Python"
+
+	shebangs map[string]string // Absolute path -> language
+	tops     map[string]string // Repo dir -> work tree root
+}
+
+// DefaultLanguages returns the built-in classification rules.
+func DefaultLanguages() *Languages {
+	return &Languages{
+		Extensions: map[string]string{
+			".go": "Go", ".mod": "Go", ".sum": "Go",
+			".py": "Python", ".pyi": "Python",
+			".js": "JavaScript", ".mjs": "JavaScript", ".cjs": "JavaScript", ".jsx": "JavaScript",
+			".ts": "TypeScript", ".tsx": "TypeScript",
+			".rs": "Rust", ".rb": "Ruby", ".php": "PHP", ".java": "Java",
+			".kt": "Kotlin", ".kts": "Kotlin", ".swift": "Swift", ".scala": "Scala",
+			".c": "C", ".h": "C", ".cc": "C++", ".cpp": "C++", ".cxx": "C++", ".hpp": "C++",
+			".cs": "C#", ".lua": "Lua", ".dart": "Dart", ".ex": "Elixir", ".exs": "Elixir",
+			".hs": "Haskell", ".ml": "OCaml", ".zig": "Zig", ".r": "R", ".pl": "Perl",
+			".sh": "Shell", ".bash": "Shell", ".zsh": "Shell", ".fish": "Shell", ".ps1": "PowerShell",
+			".html": "HTML", ".htm": "HTML", ".css": "CSS", ".scss": "CSS", ".sass": "CSS", ".less": "CSS",
+			".vue": "Vue", ".svelte": "Svelte", ".sql": "SQL", ".proto": "Protobuf",
+			".md": "Markdown", ".markdown": "Markdown", ".rst": "reStructuredText", ".txt": "Text",
+			".json": "JSON", ".yaml": "YAML", ".yml": "YAML", ".toml": "TOML", ".xml": "XML",
+			".tf": "Terraform", ".nix": "Nix",
+		},
+		Filenames: map[string]string{
+			"Makefile": "Makefile", "GNUmakefile": "Makefile",
+			"Dockerfile": "Dockerfile", "Containerfile": "Dockerfile",
+			"Rakefile": "Ruby", "Gemfile": "Ruby", "Jenkinsfile": "Groovy",
+		},
+		Interpreters: map[string]string{
+			"sh": "Shell", "bash": "Shell", "zsh": "Shell", "dash": "Shell", "fish": "Shell",
+			"python": "Python", "python3": "Python", "python2": "Python",
+			"node": "JavaScript", "deno": "TypeScript", "ruby": "Ruby", "perl": "Perl",
+			"php": "PHP", "lua": "Lua", "Rscript": "R",
+		},
+	}
+}
+
+// Set adds a rule. Patterns starting with "." match extensions, "#!" matches
+// an interpreter and anything else matches a file name.
+func (l *Languages) Set(pattern, language string) {
+	switch {
+	case strings.HasPrefix(pattern, "#!"):
+		l.Interpreters[strings.TrimPrefix(pattern, "#!")] = language
+	case strings.HasPrefix(pattern, "."):
+		l.Extensions[strings.ToLower(pattern)] = language
+	default:
+		l.Filenames[pattern] = language
+	}
+}
+
+// Detect returns the language of a path from git (relative to the root of the
+// repository at dir). Files without an extension are checked for a shebang in
+// the work tree; deleted files fall back to OtherLanguage.
+func (l *Languages) Detect(dir, file string) string {
+	file = renamedPath(file)
+	base := path.Base(file)
+	if lang, ok := l.Filenames[base]; ok {
+		return lang
+	}
+	if ext := strings.ToLower(path.Ext(base)); ext != "" {
+		if lang, ok := l.Extensions[ext]; ok {
+			return lang
+		}
+		return OtherLanguage
+	}
+	return l.shebang(dir, file)
+}
+
+// fingerprint identifies the rules, so cached classifications are dropped
+// when they change.
+func (l *Languages) fingerprint() string {
+	var rules []string
+	for kind, m := range map[string]map[string]string{"ext": l.Extensions, "file": l.Filenames, "interp": l.Interpreters} {
+		for pattern, lang := range m {
+			rules = append(rules, kind+":"+pattern+"="+lang)
+		}
+	}
+	sort.Strings(rules)
+	sum := sha256.Sum256([]byte(strings.Join(rules, "\n")))
+	return hex.EncodeToString(sum[:8])
+}
+
+// rules returns a Languages with the same rules and its own shebang cache,
+// for use in another goroutine.
+func (l *Languages) rules() *Languages {
+	return &Languages{Extensions: l.Extensions, Filenames: l.Filenames, Interpreters: l.Interpreters}
+}
+
+func (l *Languages) shebang(dir, file string) string {
+	if l.tops == nil {
+		l.tops = make(map[string]string)
+		l.shebangs = make(map[string]string)
+	}
+	top, ok := l.tops[dir]
+	if !ok {
+		top, _ = gitOutput(dir, "rev-parse", "--show-toplevel")
+		l.tops[dir] = top
+	}
+	if top == "" {
+		return OtherLanguage
+	}
+	full := filepath.Join(top, filepath.FromSlash(file))
+	if lang, ok := l.shebangs[full]; ok {
+		return lang
+	}
+
+	lang := OtherLanguage
+	if f, err := os.Open(full); err == nil {
+		line, _ := bufio.NewReader(f).ReadString('\n')
+		f.Close()
+		if interp := interpreter(line); interp != "" {
+			if name, ok := l.Interpreters[interp]; ok {
+				lang = name
+			}
+		}
+	}
+	l.shebangs[full] = lang
+	return lang
+}
+
+// interpreter extracts the program from a shebang line, e.g.
+// "#!/usr/bin/env python3" -> "python3".
+func interpreter(line string) string {
+	if !strings.HasPrefix(line, "#!") {
+		return ""
+	}
+	fields := strings.Fields(strings.TrimPrefix(line, "#!"))
+	if len(fields) == 0 {
+		return ""
+	}
+	prog := path.Base(fields[0])
+	if prog == "env" {
+		for _, f := range fields[1:] {
+			if !strings.HasPrefix(f, "-") {
+				return f
+			}
+		}
+		return ""
+	}
+	return prog
+}
+
+// renamedPath resolves --numstat rename notation ("a => b", "dir/{a => b}/f")
+// to the new path.
+func renamedPath(p string) string {
+	if !strings.Contains(p, " => ") {
+		return p
+	}
+	if open := strings.Index(p, "{"); open >= 0 {
+		if end := strings.Index(p[open:], "}"); end >= 0 {
+			_, to, _ := strings.Cut(p[open+1:open+end], " => ")
+			p = p[:open] + to + p[open+end+1:]
+			return strings.ReplaceAll(p, "//", "/")
+		}
+	}
+	_, to, _ := strings.Cut(p, " => ")
+	return to
+}
+
+// Share is the work attributed to one language or directory.
+type Share struct {
+	Name      string
+	Commits   int // Commits touching it
+	Additions int
+	Deletions int
+}
+
+// Lines returns the changed lines (added plus deleted).
+func (s Share) Lines() int {
+	return s.Additions + s.Deletions
+}
+
+// MonthShare holds the changed lines per language of one calendar month.
+type MonthShare struct {
+	Month time.Time // First day of the month
+	Lines map[string]int
+}
+
+// ByLanguage attributes changed lines between start and end to languages,
+// sorted by lines changed. Zero bounds are open-ended.
+func (a Activity) ByLanguage(start, end time.Time) []Share {
+	return a.shares(start, end, func(c Contribution) []Share { return c.Languages })
+}
+
+// ByDirectory attributes changed lines to top-level directories. Files at the
+// repository root count as ".".
+func (a Activity) ByDirectory(start, end time.Time) []Share {
+	return a.shares(start, end, func(c Contribution) []Share { return c.Directories })
+}
+
+// LanguageTrend returns the changed lines per language for every month
+// between start and end, oldest first.
+func (a Activity) LanguageTrend(start, end time.Time) []MonthShare {
+	if start.IsZero() {
+		start = a.FirstDay()
+	}
+	if end.IsZero() || start.IsZero() {
+		return nil
+	}
+	var months []MonthShare
+	index := make(map[string]int)
+	for m := time.Date(start.Year(), start.Month(), 1, 0, 0, 0, 0, time.UTC); !m.After(Date(end)); m = m.AddDate(0, 1, 0) {
+		index[m.Format("2006-01")] = len(months)
+		months = append(months, MonthShare{Month: m, Lines: make(map[string]int)})
+	}
+
+	a.each(start, end, func(day time.Time, c Contribution) {
+		month := months[index[day.Format("2006-01")]]
+		for _, l := range c.Languages {
+			month.Lines[l.Name] += l.Lines()
+		}
+	})
+	return months
+}
+
+// shares sums the shares picked from each contribution and sorts them by lines changed.
+func (a Activity) shares(start, end time.Time, pick func(Contribution) []Share) []Share {
+	byName := make(map[string]*Share)
+	a.each(start, end, func(_ time.Time, c Contribution) {
+		for _, share := range pick(c) {
+			s, ok := byName[share.Name]
+			if !ok {
+				s = &Share{Name: share.Name}
+				byName[share.Name] = s
+			}
+			s.Commits += share.Commits
+			s.Additions += share.Additions
+			s.Deletions += share.Deletions
+		}
+	})
+
+	shares := make([]Share, 0, len(byName))
+	for _, s := range byName {
+		shares = append(shares, *s)
+	}
+	sort.Slice(shares, func(i, j int) bool {
+		if shares[i].Lines() != shares[j].Lines() {
+			return shares[i].Lines() > shares[j].Lines()
+		}
+		return shares[i].Name < shares[j].Name
+	})
+	return shares
+}
+
+// each calls fn for every contribution between start and end (inclusive days).
+func (a Activity) each(start, end time.Time, fn func(time.Time, Contribution)) {
+	if !start.IsZero() {
+		start = Date(start)
+	}
+	if !end.IsZero() {
+		end = Date(end)
+	}
+	for day, contributions := range a {
+		d, err := ParseDay(day)
+		if err != nil {
+			continue
+		}
+		if (!start.IsZero() && d.Before(start)) || (!end.IsZero() && d.After(end)) {
+			continue
+		}
+		for _, c := range contributions {
+			fn(d, c)
+		}
+	}
+}
+
+// directory returns the top-level directory of a file, "." at the root.
+func directory(file string) string {
+	dir := path.Dir(file)
+	if dir == "." {
+		return "."
+	}
+	top, _, _ := strings.Cut(dir, "/")
+	return top + "/"
+}
@@ -0,0 +1,98 @@
+package stats
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestDetectLanguage(t *testing.T) {
+	langs := DefaultLanguages()
+	langs.Set(".tmpl", "Go Template")
+	langs.Set("#!deno", "Deno")
+	langs.Set("Justfile", "Just")
+
+	cases := map[string]string{
+		"main.go":                 "Go",
+		"web/App.TSX":             "TypeScript",
+		"views/page.tmpl":         "Go Template",
+		"Justfile":                "Just",
+		"build/Dockerfile":        "Dockerfile",
+		"logo.png":                OtherLanguage,
+		"src/{old.js => new.ts}":  "TypeScript",
+		"docs/a.txt => docs/b.md": "Markdown",
+	}
+	for file, want := range cases {
+		if got := langs.Detect("", file); got != want {
+			t.Errorf("Detect(%q) = %q, want %q", file, got, want)
+		}
+	}
+}
+
+func TestDetectShebang(t *testing.T) {
+	dir, _ := gitRepo(t)
+	scripts := map[string]string{
+		"deploy": "#!/usr/bin/env -S python3 -u\nprint()\n",
+		"run":    "#!/bin/bash\necho\n",
+		"serve":  "#!/usr/bin/env deno\n",
+		"README": "plain text\n",
+	}
+	for name, content := range scripts {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o755); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	langs := DefaultLanguages()
+	langs.Set("#!deno", "Deno")
+	want := map[string]string{"deploy": "Python", "run": "Shell", "serve": "Deno", "README": OtherLanguage, "missing": OtherLanguage}
+	for name, lang := range want {
+		if got := langs.Detect(dir, name); got != lang {
+			t.Errorf("Detect(%q) = %q, want %q", name, got, lang)
+		}
+	}
+}
+
+func TestLanguageAndDirectoryShares(t *testing.T) {
+	commits := []Commit{
+		{AuthorTime: mustParse(t, "2024-03-01T10:00:00Z"), Files: []FileChange{
+			{Path: "cmd/app/main.go", Additions: 10, Deletions: 2},
+			{Path: "internal/x/x.go", Additions: 5},
+			{Path: "README.md", Additions: 3},
+		}},
+		{AuthorTime: mustParse(t, "2024-04-10T10:00:00Z"), Files: []FileChange{
+			{Path: "internal/x/x_test.go", Additions: 1, Deletions: 1},
+			{Path: "web/app.ts", Additions: 30},
+		}},
+	}
+	activity := aggregate("", commits, Options{Languages: DefaultLanguages()})
+
+	march := time.Date(2024, 3, 31, 0, 0, 0, 0, time.Local)
+	byLang := activity.ByLanguage(time.Time{}, march)
+	if len(byLang) != 2 || byLang[0].Name != "Go" || byLang[0].Lines() != 17 || byLang[0].Commits != 1 {
+		t.Errorf("unexpected languages: %+v", byLang)
+	}
+
+	byDir := activity.ByDirectory(time.Time{}, time.Time{})
+	want := []string{"web/", "cmd/", "internal/", "."}
+	if len(byDir) != len(want) {
+		t.Fatalf("unexpected directories: %+v", byDir)
+	}
+	for i, name := range want {
+		if byDir[i].Name != name {
+			t.Errorf("directory %d = %q, want %q", i, byDir[i].Name, name)
+		}
+	}
+	if byDir[2].Commits != 2 {
+		t.Errorf("internal/ was touched by 2 commits, got %d", byDir[2].Commits)
+	}
+
+	trend := activity.LanguageTrend(time.Date(2024, 2, 15, 0, 0, 0, 0, time.Local), time.Date(2024, 4, 30, 0, 0, 0, 0, time.Local))
+	if len(trend) != 3 {
+		t.Fatalf("expected Feb, Mar and Apr, got %d months", len(trend))
+	}
+	if trend[1].Lines["Go"] != 17 || trend[2].Lines["TypeScript"] != 30 || len(trend[0].Lines) != 0 {
+		t.Errorf("unexpected trend: %+v", trend)
+	}
+}
@@ -0,0 +1,146 @@
+package stats
+
+import (
+	"fmt"
+	"sort"
+	"time"
+)
+
+// Metric selects what a heatmap day measures.
+type Metric int
+
+const (
+	MetricCommits   Metric = iota // Number of commits
+	MetricAdditions               // Lines added
+	MetricDeletions               // Lines deleted
+	MetricNet                     // Lines added minus lines deleted
+)
+
+// Metrics lists all metrics in cycle order.
+var Metrics = []Metric{MetricCommits, MetricAdditions, MetricDeletions, MetricNet}
+
+// String returns the flag name of the metric.
+func (m Metric) String() string {
+	switch m {
+	case MetricAdditions:
+		return "added"
+	case MetricDeletions:
+		return "deleted"
+	case MetricNet:
+		return "net"
+	default:
+		return "commits"
+	}
+}
+
+// Describe formats a value of the metric, e.g. "3 commits" or "+120 lines".
+func (m Metric) Describe(v int) string {
+	switch m {
+	case MetricAdditions:
+		return fmt.Sprintf("+%d lines", v)
+	case MetricDeletions:
+		return fmt.Sprintf("-%d lines", v)
+	case MetricNet:
+		return fmt.Sprintf("%+d lines net", v)
+	default:
+		return fmt.Sprintf("%d commits", v)
+	}
+}
+
+// ParseMetric parses a metric flag name.
+func ParseMetric(name string) (Metric, bool) {
+	for _, m := range Metrics {
+		if m.String() == name {
+			return m, true
+		}
+	}
+	return MetricCommits, false
+}
+
+// Value returns the metric for a day (YYYY-MM-DD). MetricNet may be negative.
+func (a Activity) Value(date string, metric Metric) int {
+	total := 0
+	for _, c := range a[date] {
+		switch metric {
+		case MetricCommits:
+			total += c.Commits
+		case MetricAdditions:
+			total += c.Additions
+		case MetricDeletions:
+			total += c.Deletions
+		case MetricNet:
+			total += c.Additions - c.Deletions
+		}
+	}
+	return total
+}
+
+// HeatPalette holds the hex background colors of heat levels 0 (none) to 5
+// (exceptional). It is shared by the terminal heatmap and the exporters.
+var HeatPalette = []string{
+	"#262626", // Neutral Dark Grey (Distinct from Blue)
+	"#1E3A5F", // Dark Blue
+	"#0369A1", // Sky-700
+	"#0EA5E9", // Sky-500
+	"#38BDF8", // Sky-400
+	"#F59E0B", // Gold (Exceptional)
+}
+
+// Buckets maps metric values to heat levels 0 (none) to 5 (exceptional).
+// Limits are the upper bounds of levels 1-4; anything above is level 5.
+type Buckets struct {
+	Limits [4]int
+}
+
+// Level returns the heat level of v. Negative values are ranked by magnitude.
+func (b Buckets) Level(v int) int {
+	if v < 0 {
+		v = -v
+	}
+	if v == 0 {
+		return 0
+	}
+	for i, limit := range b.Limits {
+		if v <= limit {
+			return i + 1
+		}
+	}
+	return 5
+}
+
+// Scale computes buckets from the non-zero day values between start and end,
+// using the 25th, 50th, 75th and 95th percentiles so the colors follow the data.
+func (a Activity) Scale(metric Metric, start, end time.Time) Buckets {
+	var values []int
+	for _, day := range Days(start, end) {
+		v := a.Value(day.Format("2006-01-02"), metric)
+		if v < 0 {
+			v = -v
+		}
+		if v > 0 {
+			values = append(values, v)
+		}
+	}
+	return ScaleValues(values)
+}
+
+// ScaleValues computes percentile buckets from a set of positive values.
+func ScaleValues(values []int) Buckets {
+	if len(values) == 0 {
+		return Buckets{Limits: [4]int{1, 2, 3, 4}}
+	}
+	sort.Ints(values)
+
+	var b Buckets
+	prev := 0
+	for i, p := range []float64{0.25, 0.50, 0.75, 0.95} {
+		limit := values[int(p*float64(len(values)-1))]
+		// Keep limits strictly increasing so every level is reachable
+		if limit <= prev {
+			limit = prev + 1
+		}
+		b.Limits[i] = limit
+		prev = limit
+	}
+	return b
+}
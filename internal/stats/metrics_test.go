@@ -0,0 +1,47 @@
+package stats
+
+import "testing"
+
+func TestActivityValue(t *testing.T) {
+	activity := Activity{
+		"2024-03-01": {
+			{Commits: 1, Additions: 10, Deletions: 2},
+			{Commits: 1, Additions: 1, Deletions: 30},
+		},
+	}
+	tests := []struct {
+		metric Metric
+		want   int
+	}{
+		{MetricCommits, 2},
+		{MetricAdditions, 11},
+		{MetricDeletions, 32},
+		{MetricNet, -21},
+	}
+	for _, tt := range tests {
+		if got := activity.Value("2024-03-01", tt.metric); got != tt.want {
+			t.Errorf("Value(%s) = %d, want %d", tt.metric, got, tt.want)
+		}
+	}
+}
+
+func TestScaleValues(t *testing.T) {
+	// 1..100: limits should follow the percentiles
+	var values []int
+	for i := 1; i <= 100; i++ {
+		values = append(values, i)
+	}
+	b := ScaleValues(values)
+	if b.Limits != [4]int{25, 50, 75, 95} {
+		t.Errorf("unexpected limits: %v", b.Limits)
+	}
+	if b.Level(0) != 0 || b.Level(1) != 1 || b.Level(60) != 3 || b.Level(-60) != 3 || b.Level(500) != 5 {
+		t.Errorf("unexpected levels: %d %d %d %d", b.Level(0), b.Level(1), b.Level(60), b.Level(500))
+	}
+
+	// Identical values still produce increasing limits
+	b = ScaleValues([]int{3, 3, 3})
+	if b.Limits != [4]int{3, 4, 5, 6} {
+		t.Errorf("expected strictly increasing limits, got %v", b.Limits)
+	}
+}
@@ -0,0 +1,136 @@
+package stats
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// skipDirs are never scanned for repositories.
+var skipDirs = map[string]bool{
+	"node_modules": true,
+	"vendor":       true,
+}
+
+// DiscoverRepos returns the git repositories at or below root, up to maxDepth
+// directory levels deep. Repositories are not searched for nested repositories.
+func DiscoverRepos(root string, maxDepth int) ([]string, error) {
+	root, err := filepath.Abs(root)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := os.Stat(root); err != nil {
+		return nil, err
+	}
+
+	var repos []string
+	var walk func(dir string, depth int)
+	walk = func(dir string, depth int) {
+		// .git is a directory in normal clones and a file in worktrees/submodules
+		if _, err := os.Stat(filepath.Join(dir, ".git")); err == nil {
+			repos = append(repos, dir)
+			return
+		}
+		if depth >= maxDepth {
+			return
+		}
+		entries, err := os.ReadDir(dir)
+		if err != nil {
+			return // Unreadable directories are skipped
+		}
+		for _, e := range entries {
+			name := e.Name()
+			if !e.IsDir() || strings.HasPrefix(name, ".") || skipDirs[name] {
+				continue
+			}
+			walk(filepath.Join(dir, name), depth+1)
+		}
+	}
+	walk(root, 0)
+
+	sort.Strings(repos)
+	return repos, nil
+}
+
+// CollectRepos reads the history of every repository with at most parallel
+// concurrent git processes and merges it into one Activity.
+// With useCache, each repository's .git/raven cache is used (see LoadActivity).
+// Repositories that fail are reported in the returned errors and skipped.
+func CollectRepos(repos []string, parallel int, useCache bool, opts Options) (Activity, []error) {
+	load := GetCommitCountsIn
+	if useCache {
+		load = LoadActivity
+	}
+
+	if parallel < 1 {
+		parallel = 1
+	}
+
+	merged := make(Activity)
+	var (
+		mu   sync.Mutex
+		wg   sync.WaitGroup
+		errs []error
+		sem  = make(chan struct{}, parallel)
+	)
+	for _, repo := range repos {
+		wg.Add(1)
+		go func(repo string) {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+
+			// Languages caches shebangs and is not safe for concurrent use
+			repoOpts := opts
+			repoOpts.Languages = opts.languages().rules()
+			activity, err := load(repo, repoOpts)
+
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				errs = append(errs, fmt.Errorf("%s: %w", repo, err))
+				return
+			}
+			merged.Merge(activity)
+		}(repo)
+	}
+	wg.Wait()
+	return merged, errs
+}
+
+// Merge adds the contributions of other into a.
+func (a Activity) Merge(other Activity) {
+	for day, contributions := range other {
+		for _, c := range contributions {
+			a.add(day, c)
+		}
+	}
+}
+
+// RepoCount is the number of commits of one repository.
+type RepoCount struct {
+	Repo    string
+	Commits int
+}
+
+// ByRepo breaks down the commits of a day by repository, most active first.
+func (a Activity) ByRepo(date string) []RepoCount {
+	counts := make(map[string]int)
+	for _, c := range a[date] {
+		counts[c.Repo] += c.Commits
+	}
+	var breakdown []RepoCount
+	for repo, n := range counts {
+		breakdown = append(breakdown, RepoCount{Repo: repo, Commits: n})
+	}
+	sort.Slice(breakdown, func(i, j int) bool {
+		if breakdown[i].Commits != breakdown[j].Commits {
+			return breakdown[i].Commits > breakdown[j].Commits
+		}
+		return breakdown[i].Repo < breakdown[j].Repo
+	})
+	return breakdown
+}
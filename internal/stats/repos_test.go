@@ -0,0 +1,56 @@
+package stats
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestDiscoverRepos(t *testing.T) {
+	root := t.TempDir()
+	for _, dir := range []string{
+		"api/.git",
+		"web/.git",
+		"web/nested/.git",       // Inside a repo: not searched
+		"group/tools/.git",      // Two levels deep
+		"node_modules/pkg/.git", // Skipped directory
+		"deep/a/b/c/.git",       // Beyond max depth
+		"notarepo/src",
+	} {
+		if err := os.MkdirAll(filepath.Join(root, dir), 0o755); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	repos, err := DiscoverRepos(root, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{
+		filepath.Join(root, "api"),
+		filepath.Join(root, "group/tools"),
+		filepath.Join(root, "web"),
+	}
+	if len(repos) != len(want) {
+		t.Fatalf("DiscoverRepos() = %v, want %v", repos, want)
+	}
+	for i := range want {
+		if repos[i] != want[i] {
+			t.Errorf("repos[%d] = %s, want %s", i, repos[i], want[i])
+		}
+	}
+}
+
+func TestMergeAndByRepo(t *testing.T) {
+	merged := Activity{"2024-03-01": {{Repo: "api", Commits: 1}}}
+	merged.Merge(Activity{"2024-03-01": {{Repo: "web", Commits: 2}}, "2024-03-02": {{Repo: "web", Commits: 1}}})
+	merged.Merge(Activity{"2024-03-01": {{Repo: "api", Commits: 1}}})
+
+	if merged.Count("2024-03-01") != 4 || merged.Count("2024-03-02") != 1 || len(merged["2024-03-01"]) != 2 {
+		t.Fatalf("unexpected merged counts: %v", merged)
+	}
+	breakdown := merged.ByRepo("2024-03-01")
+	if len(breakdown) != 2 || breakdown[0] != (RepoCount{Repo: "api", Commits: 2}) || breakdown[1] != (RepoCount{Repo: "web", Commits: 2}) {
+		t.Errorf("unexpected breakdown: %v", breakdown)
+	}
+}
@@ -2,42 +2,330 @@ package stats
 
 import (
 	"os/exec"
+	"sort"
+	"strconv"
 	"strings"
 	"time"
 )
 
-// GetCommitCounts returns a map of date (YYYY-MM-DD) to commit count.
-func GetCommitCounts() (map[string]int, error) {
-	// git log --pretty=format:%ad --date=short
-	cmd := exec.Command("git", "log", "--pretty=format:%ad", "--date=short")
+// Commit is a single commit as seen by the statistics engine. Commits are
+// only kept while aggregating and for the drill-down of a single day (see
+// LoadDay); Activity holds Contributions.
+type Commit struct {
+	Repo         string // Repository directory, "" for the current one
+	Hash         string
+	ShortHash    string
+	Author       string
+	Email        string
+	Time         time.Time // Bucketing time, see Bucketing (author date by default)
+	AuthorTime   time.Time // With the author's UTC offset
+	CommitTime   time.Time // With the committer's UTC offset
+	Subject      string
+	FilesChanged int
+	Additions    int
+	Deletions    int
+	Files        []FileChange
+}
+
+// FileChange holds the line counts of one file in a commit.
+// Binary files have zero additions and deletions.
+type FileChange struct {
+	Path      string
+	Additions int
+	Deletions int
+}
+
+// Contribution is the work of one author in one repository on one day.
+// Languages and Directories are sorted by name.
+type Contribution struct {
+	Repo        string `json:"-"` // Repository directory, "" for the current one
+	Author      string
+	Email       string
+	Commits     int
+	Additions   int
+	Deletions   int
+	Hours       [24]int // Commits per hour of the bucketing time
+	Languages   []Share // Changed lines per language
+	Directories []Share // Changed lines per top-level directory, see directory
+}
+
+// Activity groups contributions by day (YYYY-MM-DD) in the bucketing used to
+// load it.
+type Activity map[string][]Contribution
+
+// Count returns the number of commits on the given day.
+func (a Activity) Count(date string) int {
+	total := 0
+	for _, c := range a[date] {
+		total += c.Commits
+	}
+	return total
+}
+
+// Options control how commits are aggregated into Activity.
+type Options struct {
+	Bucketing Bucketing
+	Languages *Languages // Classification rules, nil for DefaultLanguages
+}
+
+func (o Options) languages() *Languages {
+	if o.Languages == nil {
+		return DefaultLanguages()
+	}
+	return o.Languages
+}
+
+// Field/record separators that never appear in commit metadata.
+const (
+	recordSep = "\x1e"
+	fieldSep  = "\x1f"
+)
+
+// GetCommitCounts returns the history of the current repository grouped by
+// day (YYYY-MM-DD). Author names and emails are resolved through .mailmap.
+func GetCommitCounts(opts Options) (Activity, error) {
+	return GetCommitCountsIn("", opts)
+}
+
+// GetCommitCountsIn is GetCommitCounts for the repository at dir ("" = current directory).
+// Contributions are tagged with dir as their Repo.
+func GetCommitCountsIn(dir string, opts Options) (Activity, error) {
+	activity, err := logActivity(dir, opts)
+	if err != nil {
+		return nil, err
+	}
+	activity.tagRepo(dir)
+	return activity, nil
+}
+
+// logActivity aggregates git log in dir, optionally limited to revs (e.g. "abc123..HEAD").
+func logActivity(dir string, opts Options, revs ...string) (Activity, error) {
+	commits, err := logCommits(dir, revs...)
+	if err != nil {
+		return nil, err
+	}
+	return aggregate(dir, commits, opts), nil
+}
+
+// logCommits runs git log in dir with the given extra arguments.
+func logCommits(dir string, args ...string) ([]Commit, error) {
+	// git log --pretty=format:<record> --numstat, dates in strict ISO 8601 with offsets
+	format := "--pretty=format:" + recordSep + "%H" + fieldSep + "%h" + fieldSep + "%aN" + fieldSep + "%aE" + fieldSep + "%aI" + fieldSep + "%cI" + fieldSep + "%s"
+	cmd := exec.Command("git", append([]string{"log", format, "--numstat"}, args...)...)
+	cmd.Dir = dir
 	out, err := cmd.Output()
 	if err != nil {
 		return nil, err
 	}
+	return parseCommits(string(out)), nil
+}
+
+// LoadDay reads the commits behind the contributions of one day, newest first.
+// Only commits by the contributions' authors in their repositories are kept,
+// so the contributions of a filtered Activity yield the filtered commits.
+func LoadDay(day time.Time, contributions []Contribution, b Bucketing) ([]Commit, error) {
+	key := Date(day).Format("2006-01-02")
+	emails := make(map[string]map[string]bool) // Repo -> lowercase author emails
+	var repos []string
+	for _, c := range contributions {
+		if emails[c.Repo] == nil {
+			emails[c.Repo] = make(map[string]bool)
+			repos = append(repos, c.Repo)
+		}
+		emails[c.Repo][strings.ToLower(c.Email)] = true
+	}
+	sort.Strings(repos)
+
+	// Commits are not committed before they are authored, and no UTC offset
+	// exceeds 14 hours: two days of slack cover the day in every bucketing.
+	args := []string{"--since=" + Date(day).AddDate(0, 0, -2).Format("2006-01-02")}
+	if b.Committer {
+		args = append(args, "--until="+Date(day).AddDate(0, 0, 2).Format("2006-01-02"))
+	}
+
+	var commits []Commit
+	for _, repo := range repos {
+		logged, err := logCommits(repo, args...)
+		if err != nil {
+			return nil, err
+		}
+		for _, c := range logged {
+			c.Repo = repo
+			c.Time = b.Time(c)
+			if c.Time.Format("2006-01-02") == key && emails[repo][strings.ToLower(c.Email)] {
+				commits = append(commits, c)
+			}
+		}
+	}
+	sort.SliceStable(commits, func(i, j int) bool {
+		return commits[i].Time.After(commits[j].Time)
+	})
+	return commits, nil
+}
+
+// tagRepo sets the Repo of every contribution to dir.
+func (a Activity) tagRepo(dir string) {
+	for _, contributions := range a {
+		for i := range contributions {
+			contributions[i].Repo = dir
+		}
+	}
+}
 
-	counts := make(map[string]int)
-	lines := strings.Split(string(out), "\n")
-	for _, line := range lines {
-		date := strings.TrimSpace(line)
-		if date == "" {
+// parseCommits parses the output of logCommits, newest first.
+func parseCommits(out string) []Commit {
+	var commits []Commit
+	for _, record := range strings.Split(out, recordSep) {
+		if strings.TrimSpace(record) == "" {
+			continue
+		}
+		// First line holds the fields, the rest are --numstat lines.
+		header, rest, _ := strings.Cut(record, "\n")
+		fields := strings.Split(header, fieldSep)
+		if len(fields) < 7 {
+			continue
+		}
+		authored, err := time.Parse(time.RFC3339, strings.TrimSpace(fields[4]))
+		if err != nil {
 			continue
 		}
-		counts[date]++
+		committed, err := time.Parse(time.RFC3339, strings.TrimSpace(fields[5]))
+		if err != nil {
+			committed = authored
+		}
+
+		c := Commit{
+			Hash:       fields[0],
+			ShortHash:  fields[1],
+			Author:     fields[2],
+			Email:      fields[3],
+			Time:       authored,
+			AuthorTime: authored,
+			CommitTime: committed,
+			Subject:    strings.Join(fields[6:], fieldSep),
+		}
+		for _, line := range strings.Split(rest, "\n") {
+			// Format: "<added>\t<deleted>\t<path>", "-" for binary files
+			parts := strings.SplitN(line, "\t", 3)
+			if len(parts) != 3 {
+				continue
+			}
+			adds, _ := strconv.Atoi(parts[0])
+			dels, _ := strconv.Atoi(parts[1])
+			c.Files = append(c.Files, FileChange{Path: parts[2], Additions: adds, Deletions: dels})
+			c.Additions += adds
+			c.Deletions += dels
+		}
+		c.FilesChanged = len(c.Files)
+		commits = append(commits, c)
 	}
+	return commits
+}
 
-	return counts, nil
+// aggregate groups commits of the repository at dir into contributions per
+// bucketing day and author, classifying their files with opts.Languages.
+func aggregate(dir string, commits []Commit, opts Options) Activity {
+	langs := opts.languages()
+	activity := make(Activity)
+	for _, c := range commits {
+		t := opts.Bucketing.Time(c)
+		contribution := Contribution{
+			Repo:      dir,
+			Author:    c.Author,
+			Email:     c.Email,
+			Commits:   1,
+			Additions: c.Additions,
+			Deletions: c.Deletions,
+		}
+		contribution.Hours[t.Hour()] = 1
+		for _, f := range c.Files {
+			contribution.Languages = addShare(contribution.Languages, langs.Detect(dir, f.Path), f)
+			contribution.Directories = addShare(contribution.Directories, directory(renamedPath(f.Path)), f)
+		}
+		activity.add(t.Format("2006-01-02"), contribution)
+	}
+	return activity
 }
 
-// GetLastSixMonths returns a slice of dates for the last 6 months (approx 180 days).
-func GetLastSixMonths() []time.Time {
-	var dates []time.Time
-	now := time.Now()
-	// Start from 6 months ago
-	start := now.AddDate(0, -6, 0)
+// addShare adds the lines of f to the share called name. The commit is
+// counted once per share, so it must be called for a single commit's files.
+func addShare(shares []Share, name string, f FileChange) []Share {
+	i := sort.Search(len(shares), func(i int) bool { return shares[i].Name >= name })
+	if i == len(shares) || shares[i].Name != name {
+		shares = append(shares, Share{})
+		copy(shares[i+1:], shares[i:])
+		shares[i] = Share{Name: name, Commits: 1}
+	}
+	shares[i].Additions += f.Additions
+	shares[i].Deletions += f.Deletions
+	return shares
+}
 
-	// Iterate until now
-	for d := start; !d.After(now); d = d.AddDate(0, 0, 1) {
+// mergeShares adds the shares of other into shares, keeping them sorted by name.
+func mergeShares(shares, other []Share) []Share {
+	for _, o := range other {
+		i := sort.Search(len(shares), func(i int) bool { return shares[i].Name >= o.Name })
+		if i == len(shares) || shares[i].Name != o.Name {
+			shares = append(shares, Share{})
+			copy(shares[i+1:], shares[i:])
+			shares[i] = Share{Name: o.Name}
+		}
+		shares[i].Commits += o.Commits
+		shares[i].Additions += o.Additions
+		shares[i].Deletions += o.Deletions
+	}
+	return shares
+}
+
+// add merges c into the contribution of the same repository and author on day.
+func (a Activity) add(day string, c Contribution) {
+	for i, existing := range a[day] {
+		if existing.Repo == c.Repo && existing.Author == c.Author && strings.EqualFold(existing.Email, c.Email) {
+			existing.Commits += c.Commits
+			existing.Additions += c.Additions
+			existing.Deletions += c.Deletions
+			for h := range existing.Hours {
+				existing.Hours[h] += c.Hours[h]
+			}
+			existing.Languages = mergeShares(existing.Languages, c.Languages)
+			existing.Directories = mergeShares(existing.Directories, c.Directories)
+			a[day][i] = existing
+			return
+		}
+	}
+	// Copy the shares so merging never writes into another Activity
+	c.Languages = mergeShares(nil, c.Languages)
+	c.Directories = mergeShares(nil, c.Directories)
+	a[day] = append(a[day], c)
+}
+
+// FirstDay returns the earliest day with activity, or the zero time if there is none.
+func (a Activity) FirstDay() time.Time {
+	var first time.Time
+	for day := range a {
+		d, err := ParseDay(day)
+		if err != nil {
+			continue
+		}
+		if first.IsZero() || d.Before(first) {
+			first = d
+		}
+	}
+	return first
+}
+
+// Days returns every calendar day from start to end (inclusive), see Date.
+func Days(start, end time.Time) []time.Time {
+	var dates []time.Time
+	for d, last := Date(start), Date(end); !d.After(last); d = d.AddDate(0, 0, 1) {
 		dates = append(dates, d)
 	}
 	return dates
 }
+
+// GetLastSixMonths returns a slice of dates for the last 6 months (approx 180 days).
+func GetLastSixMonths() []time.Time {
+	now := time.Now()
+	// Start from 6 months ago
+	return Days(now.AddDate(0, -6, 0), now)
+}
@@ -20,3 +20,81 @@ func TestGetLastSixMonths(t *testing.T) {
 
 // Note: TestGetCommitCounts requires mocking exec or running in a real repo.
 // Skipping for MVP unit test suite to avoid flakiness, relying on manual verification.
+
+func TestParseAndAggregate(t *testing.T) {
+	out := "\x1eaaa111\x1faaa\x1fAlice\x1falice@example.com\x1f2024-03-01T08:00:00+01:00\x1f2024-03-01T08:00:00+01:00\x1ffeat: first\n" +
+		"8\t0\tmain.go\n2\t0\tREADME.md\n\n" +
+		"\x1ebbb222\x1fbbb\x1fBob\x1fbob@example.com\x1f2024-03-01T23:30:00-05:00\x1f2024-03-02T09:00:00+01:00\x1ffix: second\n" +
+		"0\t1\tmain.go\n-\t-\tlogo.png\n\n" +
+		"\x1eccc333\x1fccc\x1fAlice\x1falice@example.com\x1f2024-03-02T10:00:00+01:00\x1f2024-03-02T10:00:00+01:00\x1fMerge branch 'x'\n" +
+		"\x1eddd444\x1fddd\x1fAlice\x1falice@example.com\x1f2024-03-01T09:30:00+01:00\x1f2024-03-01T09:30:00+01:00\x1fdocs: third\n" +
+		"1\t1\tdocs/a.md\n\n"
+
+	commits := parseCommits(out)
+	if len(commits) != 4 {
+		t.Fatalf("expected 4 commits, got %d", len(commits))
+	}
+	if commits[0].FilesChanged != 2 || commits[0].Additions != 10 || commits[1].Deletions != 1 {
+		t.Errorf("unexpected line counts: %+v, %+v", commits[0], commits[1])
+	}
+	if commits[1].Files[1].Path != "logo.png" || commits[2].FilesChanged != 0 {
+		t.Errorf("unexpected files: %+v, %+v", commits[1].Files, commits[2].Files)
+	}
+
+	activity := aggregate("", commits, Options{})
+	if got := activity.Count("2024-03-01"); got != 3 {
+		t.Fatalf("expected 3 commits on 2024-03-01, got %d", got)
+	}
+	if got := activity.Count("2024-03-02"); got != 1 {
+		t.Fatalf("expected 1 commit on 2024-03-02, got %d", got)
+	}
+
+	day := activity["2024-03-01"]
+	if len(day) != 2 {
+		t.Fatalf("expected one contribution per author, got %+v", day)
+	}
+	alice := day[0]
+	if alice.Author != "Alice" || alice.Commits != 2 || alice.Additions != 11 || alice.Deletions != 1 {
+		t.Errorf("unexpected contribution: %+v", alice)
+	}
+	if alice.Hours[8] != 1 || alice.Hours[9] != 1 {
+		t.Errorf("unexpected hours: %v", alice.Hours)
+	}
+	want := []Share{{Name: "Go", Commits: 1, Additions: 8}, {Name: "Markdown", Commits: 2, Additions: 3, Deletions: 1}}
+	if len(alice.Languages) != 2 || alice.Languages[0] != want[0] || alice.Languages[1] != want[1] {
+		t.Errorf("Languages = %+v, want %+v", alice.Languages, want)
+	}
+	if len(alice.Directories) != 2 || alice.Directories[0].Name != "." || alice.Directories[1] != (Share{Name: "docs/", Commits: 1, Additions: 1, Deletions: 1}) {
+		t.Errorf("unexpected directories: %+v", alice.Directories)
+	}
+}
+
+func TestLoadDay(t *testing.T) {
+	dir, run := gitRepo(t)
+	commitFile(t, dir, run, "a.txt", "feat: first")
+	commitFile(t, dir, run, "b.txt", "fix: second")
+
+	activity, err := GetCommitCountsIn(dir, Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var day string
+	for d := range activity {
+		day = d
+	}
+	date, _ := ParseDay(day)
+
+	commits, err := LoadDay(date, activity[day], Bucketing{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(commits) != 2 || commits[0].Repo != dir || commits[0].Author != "Alice" {
+		t.Fatalf("unexpected commits: %+v", commits)
+	}
+
+	// Contributions of another author select none of Alice's commits
+	other := []Contribution{{Repo: dir, Email: "bob@example.com", Commits: 1}}
+	if commits, err := LoadDay(date, other, Bucketing{}); err != nil || len(commits) != 0 {
+		t.Errorf("expected no commits for another author, got %+v (%v)", commits, err)
+	}
+}
@@ -0,0 +1,80 @@
+package stats
+
+import (
+	"time"
+)
+
+// Summary holds activity metrics over a date range.
+type Summary struct {
+	Start               string  `json:"start"`
+	End                 string  `json:"end"`
+	TotalCommits        int     `json:"total_commits"`
+	ActiveDays          int     `json:"active_days"`
+	CurrentStreak       int     `json:"current_streak"`
+	LongestStreak       int     `json:"longest_streak"`
+	LongestStreakStart  string  `json:"longest_streak_start,omitempty"`
+	LongestStreakEnd    string  `json:"longest_streak_end,omitempty"`
+	BusiestDay          string  `json:"busiest_day,omitempty"`
+	BusiestDayCommits   int     `json:"busiest_day_commits"`
+	AveragePerActiveDay float64 `json:"average_per_active_day"`
+	Weekdays            [7]int  `json:"weekdays"` // Sunday first
+	Hours               [24]int `json:"hours"`    // Hour of the commit's bucketing time
+}
+
+// Summarize computes streaks, averages and distributions between start and end (inclusive days).
+func (a Activity) Summarize(start, end time.Time) Summary {
+	s := Summary{
+		Start: start.Format("2006-01-02"),
+		End:   end.Format("2006-01-02"),
+	}
+
+	streak := 0
+	var streakStart time.Time
+	for _, day := range Days(start, end) {
+		key := day.Format("2006-01-02")
+		commits := a.Count(key)
+		if commits == 0 {
+			streak = 0
+			continue
+		}
+
+		s.ActiveDays++
+		s.TotalCommits += commits
+		s.Weekdays[day.Weekday()] += commits
+		for _, c := range a[key] {
+			for hour, n := range c.Hours {
+				s.Hours[hour] += n
+			}
+		}
+		if commits > s.BusiestDayCommits {
+			s.BusiestDay = key
+			s.BusiestDayCommits = commits
+		}
+
+		if streak == 0 {
+			streakStart = day
+		}
+		streak++
+		if streak > s.LongestStreak {
+			s.LongestStreak = streak
+			s.LongestStreakStart = streakStart.Format("2006-01-02")
+			s.LongestStreakEnd = key
+		}
+	}
+
+	// The current streak ends on the last day, or the day before
+	// if nothing has been committed yet on the last day.
+	day := Date(end)
+	if a.Count(day.Format("2006-01-02")) == 0 {
+		day = day.AddDate(0, 0, -1)
+	}
+	for !day.Before(Date(start)) && a.Count(day.Format("2006-01-02")) > 0 {
+		s.CurrentStreak++
+		day = day.AddDate(0, 0, -1)
+	}
+
+	if s.ActiveDays > 0 {
+		s.AveragePerActiveDay = float64(s.TotalCommits) / float64(s.ActiveDays)
+	}
+	return s
+}
@@ -0,0 +1,54 @@
+package stats
+
+import (
+	"testing"
+	"time"
+)
+
+func TestSummarize(t *testing.T) {
+	at := func(hours ...int) Contribution {
+		c := Contribution{Commits: len(hours)}
+		for _, h := range hours {
+			c.Hours[h]++
+		}
+		return c
+	}
+	activity := Activity{
+		"2024-03-01": {at(9)},              // Fri
+		"2024-03-02": {at(10, 10), at(22)}, // Sat
+		"2024-03-03": {at(9)},              // Sun
+		"2024-03-06": {at(14)},             // Wed
+		"2024-03-07": {at(15)},             // Thu
+	}
+
+	start := time.Date(2024, 3, 1, 0, 0, 0, 0, time.Local)
+	end := time.Date(2024, 3, 8, 0, 0, 0, 0, time.Local) // Nothing yet on the last day
+
+	s := activity.Summarize(start, end)
+	if s.TotalCommits != 7 || s.ActiveDays != 5 {
+		t.Errorf("expected 7 commits on 5 days, got %d on %d", s.TotalCommits, s.ActiveDays)
+	}
+	if s.LongestStreak != 3 || s.LongestStreakStart != "2024-03-01" || s.LongestStreakEnd != "2024-03-03" {
+		t.Errorf("unexpected longest streak: %d (%s..%s)", s.LongestStreak, s.LongestStreakStart, s.LongestStreakEnd)
+	}
+	if s.CurrentStreak != 2 {
+		t.Errorf("expected current streak of 2 ending yesterday, got %d", s.CurrentStreak)
+	}
+	if s.BusiestDay != "2024-03-02" || s.BusiestDayCommits != 3 {
+		t.Errorf("unexpected busiest day: %s (%d)", s.BusiestDay, s.BusiestDayCommits)
+	}
+	if s.AveragePerActiveDay != 1.4 {
+		t.Errorf("expected average 1.4, got %v", s.AveragePerActiveDay)
+	}
+	if s.Weekdays[time.Saturday] != 3 || s.Weekdays[time.Wednesday] != 1 {
+		t.Errorf("unexpected weekday distribution: %v", s.Weekdays)
+	}
+	if s.Hours[10] != 2 || s.Hours[9] != 2 || s.Hours[22] != 1 {
+		t.Errorf("unexpected hour histogram: %v", s.Hours)
+	}
+
+	// Two days without commits break the current streak
+	if got := activity.Summarize(start, end.AddDate(0, 0, 1)).CurrentStreak; got != 0 {
+		t.Errorf("expected broken current streak, got %d", got)
+	}
+}
@@ -0,0 +1,470 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	"raven/internal/analysis"
+	"raven/internal/git"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// BranchMode is what the branch browser is currently asking for.
+type BranchMode int
+
+const (
+	BranchModeList          BranchMode = iota
+	BranchModeCreate                   // Typing a header for a new branch name
+	BranchModeRename                   // Typing the new name of the branch under the cursor
+	BranchModeUpstream                 // Typing the upstream of the branch under the cursor
+	BranchModeConfirmDelete            // Waiting for y/n before deleting marked branches
+)
+
+type branchesLoadedMsg struct {
+	branches []git.Branch
+	err      error
+}
+
+// branchActionMsg reports the outcome of a git action; the list is reloaded after it.
+type branchActionMsg struct {
+	message string
+	err     error
+}
+
+// BranchModel is the interactive branch manager.
+type BranchModel struct {
+	Branches []git.Branch
+	Cursor   int
+	Marked   map[string]bool // Local branches marked for deletion
+	Base     string          // Branch that "merged" is computed against
+	Template string          // Name template for new branches, see analysis.BranchName
+	Mode     BranchMode
+	Input    textinput.Model
+	Message  string // Outcome of the last action
+	Err      error
+	Quitting bool
+
+	offset int
+	height int
+}
+
+// InitialBranchModel creates the branch manager. Branches are loaded in Init.
+func InitialBranchModel(base, template string) BranchModel {
+	ti := textinput.New()
+	ti.Width = 60
+	ti.CharLimit = 200
+	if template == "" {
+		template = analysis.DefaultBranchTemplate
+	}
+	return BranchModel{
+		Marked:   make(map[string]bool),
+		Base:     base,
+		Template: template,
+		Input:    ti,
+		height:   24,
+	}
+}
+
+func (m BranchModel) load() tea.Cmd {
+	base := m.Base
+	return func() tea.Msg {
+		branches, err := git.GetBranches(base)
+		return branchesLoadedMsg{branches: branches, err: err}
+	}
+}
+
+func (m BranchModel) Init() tea.Cmd {
+	return m.load()
+}
+
+// Selected returns the branch under the cursor, or nil if the list is empty.
+func (m BranchModel) Selected() *git.Branch {
+	if m.Cursor < 0 || m.Cursor >= len(m.Branches) {
+		return nil
+	}
+	return &m.Branches[m.Cursor]
+}
+
+// NewBranchName is the name the create prompt would produce from its input.
+// Input that is not a Conventional header is used as the description of a "feat".
+func (m BranchModel) NewBranchName() string {
+	h, ok := analysis.ParseHeader(m.Input.Value())
+	if !ok {
+		h = analysis.Header{Type: "feat", Description: m.Input.Value()}
+	}
+	return analysis.BranchName(m.Template, h)
+}
+
+// MarkedNames returns the marked branches in list order.
+func (m BranchModel) MarkedNames() []string {
+	var names []string
+	for _, b := range m.Branches {
+		if m.Marked[b.Name] {
+			names = append(names, b.Name)
+		}
+	}
+	return names
+}
+
+func (m BranchModel) listHeight() int {
+	return max(m.height-8, 3)
+}
+
+func (m BranchModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.height = msg.Height
+		return m, nil
+
+	case branchesLoadedMsg:
+		m.Err = msg.err
+		m.Branches = msg.branches
+		m.Cursor = min(m.Cursor, max(len(m.Branches)-1, 0))
+		for name := range m.Marked {
+			if !m.isDeletable(name) {
+				delete(m.Marked, name)
+			}
+		}
+		m.scroll()
+		return m, nil
+
+	case branchActionMsg:
+		m.Message = msg.message
+		m.Err = msg.err
+		return m, m.load()
+
+	case tea.KeyMsg:
+		if msg.String() == "ctrl+c" {
+			m.Quitting = true
+			return m, tea.Quit
+		}
+		if m.Mode == BranchModeConfirmDelete {
+			return m.updateConfirm(msg)
+		}
+		if m.Mode != BranchModeList {
+			return m.updateInput(msg)
+		}
+		return m.updateList(msg)
+	}
+	return m, nil
+}
+
+func (m BranchModel) updateList(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	b := m.Selected()
+	m.Message = ""
+	m.Err = nil
+
+	switch msg.String() {
+	case "q", "esc":
+		m.Quitting = true
+		return m, tea.Quit
+
+	case "up", "k":
+		if m.Cursor > 0 {
+			m.Cursor--
+		}
+	case "down", "j":
+		if m.Cursor < len(m.Branches)-1 {
+			m.Cursor++
+		}
+	case "g", "home":
+		m.Cursor = 0
+	case "G", "end":
+		m.Cursor = max(len(m.Branches)-1, 0)
+
+	case "enter", "c": // Checkout
+		if b != nil && !b.Current {
+			return m, m.checkout(*b)
+		}
+
+	case "n": // New branch from the name template
+		return m.prompt(BranchModeCreate, "", "feat(ui): diff pane")
+
+	case "r": // Rename
+		if b != nil && !b.Remote {
+			return m.prompt(BranchModeRename, b.Name, "new-name")
+		}
+		m.Message = "Only local branches can be renamed."
+
+	case "u": // Set upstream
+		if b != nil && !b.Remote {
+			upstream := b.Upstream
+			if upstream == "" {
+				upstream = "origin/" + b.Name
+			}
+			return m.prompt(BranchModeUpstream, upstream, "origin/"+b.Name)
+		}
+		m.Message = "Only local branches have an upstream."
+
+	case " ": // Mark for deletion
+		if b != nil {
+			if m.isDeletable(b.Name) {
+				m.Marked[b.Name] = !m.Marked[b.Name]
+				if !m.Marked[b.Name] {
+					delete(m.Marked, b.Name)
+				}
+			} else {
+				m.Message = "Only local branches other than the current and base branch can be deleted."
+			}
+		}
+
+	case "m": // Mark every merged branch
+		for _, br := range m.Branches {
+			if br.Merged && m.isDeletable(br.Name) {
+				m.Marked[br.Name] = true
+			}
+		}
+		if len(m.Marked) == 0 {
+			m.Message = "No merged branches to clean up."
+		}
+
+	case "d": // Delete marked branches (or the one under the cursor)
+		if len(m.Marked) == 0 && b != nil && m.isDeletable(b.Name) {
+			m.Marked[b.Name] = true
+		}
+		if len(m.Marked) > 0 {
+			m.Mode = BranchModeConfirmDelete
+		}
+	}
+	m.scroll()
+	return m, nil
+}
+
+func (m BranchModel) prompt(mode BranchMode, value, placeholder string) (tea.Model, tea.Cmd) {
+	m.Mode = mode
+	m.Input.Placeholder = placeholder
+	m.Input.SetValue(value)
+	m.Input.CursorEnd()
+	m.Input.Focus()
+	return m, textinput.Blink
+}
+
+func (m BranchModel) updateInput(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.Type {
+	case tea.KeyEsc:
+		m.Mode = BranchModeList
+		m.Input.Blur()
+		return m, nil
+
+	case tea.KeyEnter:
+		value := strings.TrimSpace(m.Input.Value())
+		mode := m.Mode
+		m.Mode = BranchModeList
+		m.Input.Blur()
+		b := m.Selected()
+
+		switch mode {
+		case BranchModeCreate:
+			name := m.NewBranchName()
+			if name == "" {
+				return m, nil
+			}
+			return m, branchAction(func() error { return git.CreateBranch(name) }, "Created and switched to "+name)
+		case BranchModeRename:
+			if b == nil || value == "" || value == b.Name {
+				return m, nil
+			}
+			old := b.Name
+			delete(m.Marked, old)
+			return m, branchAction(func() error { return git.RenameBranch(old, value) }, "Renamed "+old+" to "+value)
+		case BranchModeUpstream:
+			if b == nil || value == "" {
+				return m, nil
+			}
+			name := b.Name
+			return m, branchAction(func() error { return git.SetUpstream(name, value) }, name+" now tracks "+value)
+		}
+		return m, nil
+	}
+
+	var cmd tea.Cmd
+	m.Input, cmd = m.Input.Update(msg)
+	return m, cmd
+}
+
+func (m BranchModel) updateConfirm(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	m.Mode = BranchModeList
+	if msg.String() != "y" && msg.String() != "Y" {
+		m.Message = "Deletion cancelled."
+		return m, nil
+	}
+
+	names := m.MarkedNames()
+	m.Marked = make(map[string]bool)
+	return m, func() tea.Msg {
+		var failed []string
+		for _, name := range names {
+			if err := git.DeleteBranch(name); err != nil {
+				failed = append(failed, name+": "+analysis.FirstLine(err.Error()))
+			}
+		}
+		msg := branchActionMsg{message: fmt.Sprintf("Deleted %d of %d branches", len(names)-len(failed), len(names))}
+		if len(failed) > 0 {
+			msg.err = fmt.Errorf("%s", strings.Join(failed, "\n"))
+		}
+		return msg
+	}
+}
+
+// checkout switches to b. A remote branch checks out its local counterpart,
+// creating a tracking branch if there is none yet.
+func (m BranchModel) checkout(b git.Branch) tea.Cmd {
+	if !b.Remote {
+		return branchAction(func() error { return git.Checkout(b.Name) }, "Switched to "+b.Name)
+	}
+	_, short, _ := strings.Cut(b.Name, "/")
+	for _, other := range m.Branches {
+		if !other.Remote && other.Name == short {
+			return branchAction(func() error { return git.Checkout(short) }, "Switched to "+short)
+		}
+	}
+	return branchAction(func() error { return git.CheckoutRemote(b.Name) }, "Switched to "+short+", tracking "+b.Name)
+}
+
+func branchAction(action func() error, success string) tea.Cmd {
+	return func() tea.Msg {
+		if err := action(); err != nil {
+			return branchActionMsg{err: err}
+		}
+		return branchActionMsg{message: success}
+	}
+}
+
+// isDeletable reports whether a branch may be deleted from the list.
+func (m BranchModel) isDeletable(name string) bool {
+	for _, b := range m.Branches {
+		if b.Name == name {
+			return !b.Remote && !b.Current && b.Name != m.Base
+		}
+	}
+	return false
+}
+
+func (m *BranchModel) scroll() {
+	h := m.listHeight()
+	if m.Cursor < m.offset {
+		m.offset = m.Cursor
+	} else if m.Cursor >= m.offset+h {
+		m.offset = m.Cursor - h + 1
+	}
+}
+
+func (m BranchModel) View() string {
+	if m.Quitting {
+		return ""
+	}
+
+	var s strings.Builder
+	title := "Branches"
+	if m.Base != "" {
+		title += "  [base: " + m.Base + "]"
+	}
+	s.WriteString(lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("#38BDF8")).Render(title) + "\n\n")
+
+	if m.Err != nil && len(m.Branches) == 0 {
+		s.WriteString(lipgloss.NewStyle().Foreground(lipgloss.Color("#F472B6")).Render("Error reading branches: "+m.Err.Error()) + "\n")
+	} else if len(m.Branches) == 0 {
+		s.WriteString("No branches yet.\n")
+	}
+
+	nameWidth := 0
+	for _, b := range m.Branches {
+		nameWidth = max(nameWidth, len([]rune(b.Name)))
+	}
+	nameWidth = min(nameWidth, 40)
+
+	end := min(m.offset+m.listHeight(), len(m.Branches))
+	for i := m.offset; i < end; i++ {
+		if i == 0 || m.Branches[i].Remote != m.Branches[i-1].Remote {
+			section := "Local"
+			if m.Branches[i].Remote {
+				section = "Remote"
+			}
+			s.WriteString(lipgloss.NewStyle().Foreground(lipgloss.Color("240")).Bold(true).Render(section) + "\n")
+		}
+		s.WriteString(m.renderBranch(i, nameWidth) + "\n")
+	}
+
+	// Prompt or status line
+	s.WriteString("\n")
+	switch m.Mode {
+	case BranchModeCreate:
+		s.WriteString("New branch (type(scope): description): " + m.Input.View() + "\n")
+		s.WriteString(lipgloss.NewStyle().Foreground(lipgloss.Color("240")).Render("→ "+m.NewBranchName()) + "\n")
+	case BranchModeRename:
+		s.WriteString("Rename to: " + m.Input.View() + "\n")
+	case BranchModeUpstream:
+		s.WriteString("Upstream: " + m.Input.View() + "\n")
+	case BranchModeConfirmDelete:
+		s.WriteString(lipgloss.NewStyle().Foreground(lipgloss.Color("#F472B6")).Bold(true).
+			Render(fmt.Sprintf("Delete %s? [y/N]", strings.Join(m.MarkedNames(), ", "))) + "\n")
+	default:
+		if m.Err != nil && len(m.Branches) > 0 {
+			s.WriteString(lipgloss.NewStyle().Foreground(lipgloss.Color("#F472B6")).Render("Error: "+m.Err.Error()) + "\n")
+		}
+		if m.Message != "" {
+			s.WriteString(lipgloss.NewStyle().Foreground(lipgloss.Color("#38BDF8")).Bold(true).Render("✔ "+m.Message) + "\n")
+		}
+	}
+
+	help := "↑/↓: navigate  •  enter: checkout  •  n: new  •  r: rename  •  u: upstream  •  space: mark  •  m: mark merged  •  d: delete  •  q: quit"
+	if m.Mode != BranchModeList && m.Mode != BranchModeConfirmDelete {
+		help = "enter: confirm  •  esc: cancel"
+	}
+	s.WriteString(lipgloss.NewStyle().Foreground(lipgloss.Color("240")).MarginTop(1).Render(help))
+	return s.String()
+}
+
+func (m BranchModel) renderBranch(i, nameWidth int) string {
+	b := m.Branches[i]
+
+	cursor := "  "
+	if i == m.Cursor {
+		cursor = "> "
+	}
+	mark := "    "
+	if m.Marked[b.Name] {
+		mark = lipgloss.NewStyle().Foreground(lipgloss.Color("#F472B6")).Render("[x] ")
+	} else if !b.Remote {
+		mark = "[ ] "
+	}
+
+	style := lipgloss.NewStyle()
+	current := "  "
+	if b.Current {
+		current = "* "
+		style = style.Foreground(lipgloss.Color("#38BDF8")).Bold(true)
+	} else if b.Remote {
+		style = style.Foreground(lipgloss.Color("#9CA3AF"))
+	}
+	if i == m.Cursor {
+		style = style.Bold(true).Underline(true)
+	}
+	name := style.Render(truncate(b.Name, nameWidth)) + strings.Repeat(" ", nameWidth-len([]rune(truncate(b.Name, nameWidth))))
+
+	// Tracking: ↑ahead ↓behind, gone, or no upstream
+	dim := lipgloss.NewStyle().Foreground(lipgloss.Color("240"))
+	var track string
+	switch {
+	case b.Gone:
+		track = lipgloss.NewStyle().Foreground(lipgloss.Color("#F472B6")).Render("gone")
+	case b.Ahead > 0 || b.Behind > 0:
+		track = lipgloss.NewStyle().Foreground(lipgloss.Color("#F59E0B")).Render(fmt.Sprintf("↑%d ↓%d", b.Ahead, b.Behind))
+	case b.Upstream != "":
+		track = dim.Render("=")
+	default:
+		track = dim.Render("-")
+	}
+	track = lipgloss.NewStyle().Width(9).Render(track)
+
+	merged := strings.Repeat(" ", 7)
+	if b.Merged && b.Name != m.Base {
+		merged = lipgloss.NewStyle().Foreground(lipgloss.Color("#34D399")).Render("merged ")
+	}
+
+	meta := dim.Render(fmt.Sprintf("%-8s %s", relativeAge(b.Date), truncate(b.Subject, 50)))
+	return cursor + mark + current + name + "  " + track + merged + meta
+}
@@ -0,0 +1,91 @@
+package ui
+
+import (
+	"testing"
+
+	"raven/internal/git"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func loadedBranchModel() BranchModel {
+	m := InitialBranchModel("main", "")
+	next, _ := m.Update(branchesLoadedMsg{branches: []git.Branch{
+		{Name: "feat/x", Current: true, Merged: false},
+		{Name: "main", Merged: true},
+		{Name: "fix/old", Merged: true},
+		{Name: "chore/older", Merged: true},
+		{Name: "wip", Merged: false},
+		{Name: "origin/fix/old", Remote: true, Merged: true},
+	}})
+	return next.(BranchModel)
+}
+
+func runes(s string) tea.KeyMsg {
+	return tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(s)}
+}
+
+func TestBranchMarkMergedAndDelete(t *testing.T) {
+	m := loadedBranchModel()
+
+	next, _ := m.Update(runes("m"))
+	m = next.(BranchModel)
+	got := m.MarkedNames()
+	if len(got) != 2 || got[0] != "fix/old" || got[1] != "chore/older" {
+		t.Fatalf("expected only merged local branches besides current and base, got %v", got)
+	}
+
+	next, _ = m.Update(runes("d"))
+	m = next.(BranchModel)
+	if m.Mode != BranchModeConfirmDelete {
+		t.Fatalf("expected delete to ask for confirmation")
+	}
+	next, cmd := m.Update(runes("n"))
+	m = next.(BranchModel)
+	if m.Mode != BranchModeList || cmd != nil || len(m.Marked) != 2 {
+		t.Errorf("expected n to cancel without deleting, keeping the marks")
+	}
+}
+
+func TestBranchMarkRejectsProtectedBranches(t *testing.T) {
+	m := loadedBranchModel()
+	for _, i := range []int{0, 1, 5} { // current, base, remote
+		m.Cursor = i
+		next, _ := m.Update(runes(" "))
+		m = next.(BranchModel)
+	}
+	if len(m.Marked) != 0 {
+		t.Errorf("expected current, base and remote branches to stay unmarked, got %v", m.MarkedNames())
+	}
+
+	m.Cursor = 4
+	next, _ := m.Update(runes(" "))
+	m = next.(BranchModel)
+	if !m.Marked["wip"] {
+		t.Errorf("expected space to mark a local branch")
+	}
+}
+
+func TestBranchCreateName(t *testing.T) {
+	m := loadedBranchModel()
+	next, _ := m.Update(runes("n"))
+	m = next.(BranchModel)
+	if m.Mode != BranchModeCreate {
+		t.Fatalf("expected n to open the create prompt")
+	}
+
+	m.Input.SetValue("fix(status): crash on empty repo")
+	if got := m.NewBranchName(); got != "fix/status-crash-on-empty-repo" {
+		t.Errorf("unexpected branch name %q", got)
+	}
+	m.Input.SetValue("Diff pane")
+	if got := m.NewBranchName(); got != "feat/diff-pane" {
+		t.Errorf("expected plain input to become a feat branch, got %q", got)
+	}
+
+	next, cmd := m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	m = next.(BranchModel)
+	if m.Mode != BranchModeList || cmd == nil {
+		t.Errorf("expected enter to create the branch")
+	}
+}
@@ -0,0 +1,176 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"raven/internal/stats"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// leaderboardSize is the number of authors shown in the leaderboard panel.
+const leaderboardSize = 10
+
+// SetAuthorFilter shows only the given (already filtered) activity in the heatmap.
+// An empty label restores the unfiltered history.
+func (m *CalendarModel) SetAuthorFilter(label string, activity stats.Activity) {
+	m.AuthorFilter = label
+	if label == "" {
+		m.Activity = m.AllActivity
+	} else {
+		m.Activity = activity
+	}
+	m.rescale()
+}
+
+// pickerAuthors lists every author in the history, most active first.
+func (m CalendarModel) pickerAuthors() []stats.AuthorStats {
+	return m.AllActivity.Leaderboard(time.Time{}, time.Time{})
+}
+
+func (m CalendarModel) updatePicker(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	authors := m.pickerAuthors()
+	switch msg.String() {
+	case "q", "esc", "a":
+		m.PickerOpen = false
+
+	case "up", "k":
+		if m.pickerCursor > 0 {
+			m.pickerCursor--
+		}
+
+	case "down", "j":
+		// Entry 0 is "Everyone"
+		if m.pickerCursor < len(authors) {
+			m.pickerCursor++
+		}
+
+	case "enter":
+		if m.pickerCursor == 0 {
+			m.SetAuthorFilter("", nil)
+		} else {
+			a := authors[m.pickerCursor-1]
+			m.SetAuthorFilter(a.Email, m.AllActivity.FilterEmail(a.Email))
+		}
+		m.PickerOpen = false
+	}
+	return m, nil
+}
+
+func (m CalendarModel) viewPicker() string {
+	header := lipgloss.NewStyle().
+		Bold(true).
+		Foreground(lipgloss.Color("#38BDF8")).
+		Render("Show contributions of")
+
+	rows := m.renderPickerRow(0, "Everyone", "", m.AuthorFilter == "")
+	for i, a := range m.pickerAuthors() {
+		meta := fmt.Sprintf("<%s> • %d commits", a.Email, a.Commits)
+		rows += m.renderPickerRow(i+1, a.Name, meta, strings.EqualFold(m.AuthorFilter, a.Email))
+	}
+
+	help := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("240")).
+		MarginTop(1).
+		Render("↑/↓: navigate  •  enter: select  •  esc: cancel")
+
+	return lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(lipgloss.Color("63")).
+		Padding(1, 2).
+		Render(header + "\n\n" + rows + help)
+}
+
+func (m CalendarModel) renderPickerRow(i int, name, meta string, active bool) string {
+	cursor := "  "
+	style := lipgloss.NewStyle()
+	if i == m.pickerCursor {
+		cursor = "> "
+		style = style.Bold(true).Underline(true)
+	}
+	mark := "  "
+	if active {
+		mark = lipgloss.NewStyle().Foreground(lipgloss.Color("#38BDF8")).Render("✔ ")
+	}
+	return cursor + mark + style.Render(name) + " " +
+		lipgloss.NewStyle().Foreground(lipgloss.Color("240")).Render(meta) + "\n"
+}
+
+// visibleRange returns the first and last day currently drawn.
+func (m CalendarModel) visibleRange() (time.Time, time.Time) {
+	var start, end time.Time
+	if m.Layout == CalendarLayoutYear {
+		start = m.WindowStart
+		end = m.WindowStart.AddDate(0, 0, 7*m.yearWindowWeeks()-1)
+	} else {
+		start = m.ViewingMonth
+		end = m.ViewingMonth.AddDate(0, 1, -1)
+	}
+	if start.Before(m.RangeStart) {
+		start = m.RangeStart
+	}
+	if end.After(m.RangeEnd) {
+		end = m.RangeEnd
+	}
+	return start, end
+}
+
+// viewLeaderboard renders commits and lines per author over the visible range.
+func (m CalendarModel) viewLeaderboard() string {
+	if !m.ShowLeaderboard {
+		return ""
+	}
+	start, end := m.visibleRange()
+	board := m.AllActivity.Leaderboard(start, end)
+
+	header := lipgloss.NewStyle().
+		Bold(true).
+		Foreground(lipgloss.Color("#38BDF8")).
+		Render(fmt.Sprintf("Leaderboard %s – %s", start.Format("Jan 02 2006"), end.Format("Jan 02 2006")))
+
+	var rows strings.Builder
+	if len(board) == 0 {
+		rows.WriteString("No commits in this range.\n")
+	}
+	for i, a := range board {
+		if i == leaderboardSize {
+			rows.WriteString(lipgloss.NewStyle().Foreground(lipgloss.Color("240")).
+				Render(fmt.Sprintf("… and %d more", len(board)-leaderboardSize)) + "\n")
+			break
+		}
+		style := lipgloss.NewStyle()
+		if strings.EqualFold(m.AuthorFilter, a.Email) {
+			style = style.Foreground(lipgloss.Color("#38BDF8")).Bold(true)
+		}
+		name := style.Width(24).Render(truncate(a.Name, 23))
+		adds := lipgloss.NewStyle().Foreground(lipgloss.Color("#34D399")).Render(fmt.Sprintf("+%d", a.Additions))
+		dels := lipgloss.NewStyle().Foreground(lipgloss.Color("#F472B6")).Render(fmt.Sprintf("-%d", a.Deletions))
+		rows.WriteString(fmt.Sprintf("%2d. %s %5d commits  %s %s\n", i+1, name, a.Commits, adds, dels))
+	}
+
+	return "\n" + lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(lipgloss.Color("240")).
+		Padding(0, 2).
+		Render(header+"\n\n"+strings.TrimSuffix(rows.String(), "\n"))
+}
+
+// authorLabel is appended to calendar headers when a filter is active.
+func (m CalendarModel) authorLabel() string {
+	if m.AuthorFilter == "" {
+		return ""
+	}
+	return "  [" + m.AuthorFilter + "]"
+}
+
+// truncate shortens s to max runes, adding an ellipsis.
+func truncate(s string, max int) string {
+	r := []rune(s)
+	if len(r) <= max {
+		return s
+	}
+	return string(r[:max-1]) + "…"
+}
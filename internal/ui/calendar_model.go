@@ -2,104 +2,381 @@ package ui
 
 import (
 	"fmt"
+	"path/filepath"
+	"strings"
 	"time"
 
+	"raven/internal/stats"
+
+	"github.com/charmbracelet/bubbles/viewport"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 )
 
+// CalendarLayout selects how the heatmap is drawn.
+type CalendarLayout int
+
+const (
+	CalendarLayoutYear  CalendarLayout = iota // 53 weeks x 7 days of single cells
+	CalendarLayoutMonth                       // One month of large day boxes
+)
+
+// yearWeeks is the number of week columns in the year layout.
+const yearWeeks = 53
+
 // CalendarModel handles the interactive calendar view.
 type CalendarModel struct {
+	Layout       CalendarLayout
 	ViewingMonth time.Time // The first day of the month being viewed
+	WindowStart  time.Time // The first day of the first week column in the year layout
 	SelectedDate time.Time // The currently highlighted day
-	Counts       map[string]int
+	RangeStart   time.Time // Navigation is clamped to [RangeStart, RangeEnd]
+	RangeEnd     time.Time
+	Activity     stats.Activity  // Contributions shown in the heatmap (possibly filtered)
+	AllActivity  stats.Activity  // Unfiltered contributions, used by the author picker and leaderboard
+	AuthorFilter string          // Label of the active author filter, "" for everyone
+	Metric       stats.Metric    // What the heat colors measure
+	WeekStart    time.Weekday    // First row (year) or column (month) of each week
+	Bucketing    stats.Bucketing // How Activity was grouped into days, to load a day's commits
 	Quitting     bool
+
+	buckets stats.Buckets // Heat levels scaled to the data in range
+
+	// Author picker and side panels
+	PickerOpen      bool
+	ShowLeaderboard bool
+	ShowSummary     bool
+	ShowLanguages   bool
+	pickerCursor    int
+
+	// Day drill-down state
+	DayOpen    bool // Showing the commit list of SelectedDate
+	DayCursor  int
+	DiffOpen   bool // Showing the diff of the commit under DayCursor
+	dayCommits []stats.Commit
+	dayLoading bool
+	dayErr     error
+	diff       viewport.Model
+	diffHash   string
+}
+
+// dayCommitsMsg delivers the commits of a day loaded by loadDay.
+type dayCommitsMsg struct {
+	date    string
+	commits []stats.Commit
+	err     error
+}
+
+// loadDay reads the commits behind the contributions of a day from git.
+func loadDay(date time.Time, contributions []stats.Contribution, b stats.Bucketing) tea.Cmd {
+	return func() tea.Msg {
+		commits, err := stats.LoadDay(date, contributions, b)
+		return dayCommitsMsg{date: date.Format("2006-01-02"), commits: commits, err: err}
+	}
+}
+
+// InitialCalendarModel shows activity with weeks starting on weekStart.
+func InitialCalendarModel(activity stats.Activity, weekStart time.Weekday) CalendarModel {
+	today := stats.Date(time.Now())
+
+	// Range defaults to the last year, extended back to the first commit
+	start := stats.StartOfWeek(today, weekStart).AddDate(0, 0, -7*(yearWeeks-1))
+	if first := activity.FirstDay(); !first.IsZero() && first.Before(start) {
+		start = first
+	}
+
+	m := CalendarModel{
+		SelectedDate: today, // Select today initially
+		WeekStart:    weekStart,
+		Activity:     activity,
+		AllActivity:  activity,
+		diff:         viewport.New(80, 20),
+	}
+	m.SetRange(start, today)
+	return m
+}
+
+// SetRange limits navigation to [start, end] and selects end.
+// Zero values keep the current bound.
+func (m *CalendarModel) SetRange(start, end time.Time) {
+	if !start.IsZero() {
+		m.RangeStart = stats.Date(start)
+	}
+	if !end.IsZero() {
+		m.RangeEnd = stats.Date(end)
+	}
+	if m.RangeStart.After(m.RangeEnd) {
+		m.RangeStart = m.RangeEnd
+	}
+	m.SelectedDate = m.RangeEnd
+	m.WindowStart = time.Time{}
+	m.sync()
+	m.rescale()
+}
+
+// SetWeekStart changes the first day of the week and re-anchors the year window.
+func (m *CalendarModel) SetWeekStart(first time.Weekday) {
+	m.WeekStart = first
+	m.WindowStart = time.Time{}
+	m.sync()
+}
+
+// SetMetric changes what the heat colors measure.
+func (m *CalendarModel) SetMetric(metric stats.Metric) {
+	m.Metric = metric
+	m.rescale()
+}
+
+// rescale recomputes the heat levels from the data in range.
+func (m *CalendarModel) rescale() {
+	m.buckets = m.Activity.Scale(m.Metric, m.RangeStart, m.RangeEnd)
+}
+
+// selectedValue describes the metric of the selected day, e.g. "3 commits".
+func (m CalendarModel) selectedValue() string {
+	return m.Metric.Describe(m.Activity.Value(m.SelectedDate.Format("2006-01-02"), m.Metric))
+}
+
+// move shifts the selection by days, clamped to the range.
+func (m *CalendarModel) move(days int) {
+	d := m.SelectedDate.AddDate(0, 0, days)
+	if d.Before(m.RangeStart) || d.After(m.RangeEnd) {
+		return
+	}
+	m.SelectedDate = d
+	m.sync()
+}
+
+// moveMonth jumps to the 1st of the previous/next month.
+func (m *CalendarModel) moveMonth(months int) {
+	target := m.ViewingMonth.AddDate(0, months, 0)
+	if target.After(m.RangeEnd) {
+		return
+	}
+	if target.Before(m.RangeStart) {
+		// Only allow if part of that month is inside the range
+		if target.AddDate(0, 1, 0).Before(m.RangeStart) || target.AddDate(0, 1, 0).Equal(m.RangeStart) {
+			return
+		}
+		target = m.RangeStart
+	}
+	m.SelectedDate = target
+	m.sync()
 }
 
-func InitialCalendarModel(counts map[string]int) CalendarModel {
-	now := time.Now()
-	// Start viewing current month
-	startOfMonth := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, now.Location())
+// sync keeps the visible month and year window around the selection.
+func (m *CalendarModel) sync() {
+	sel := m.SelectedDate
+	m.ViewingMonth = time.Date(sel.Year(), sel.Month(), 1, 0, 0, 0, 0, time.UTC)
 
-	return CalendarModel{
-		ViewingMonth: startOfMonth,
-		SelectedDate: now, // Select today initially
-		Counts:       counts,
+	if m.WindowStart.IsZero() {
+		// Anchor the window so the range end is in the last column
+		m.WindowStart = m.startOfWeek(m.RangeEnd).AddDate(0, 0, -7*(yearWeeks-1))
+		if first := m.startOfWeek(m.RangeStart); m.WindowStart.Before(first) {
+			m.WindowStart = first
+		}
+	}
+	week := m.startOfWeek(sel)
+	if week.Before(m.WindowStart) {
+		m.WindowStart = week
+	} else if last := m.WindowStart.AddDate(0, 0, 7*(yearWeeks-1)); week.After(last) {
+		m.WindowStart = week.AddDate(0, 0, -7*(yearWeeks-1))
 	}
 }
 
+// startOfWeek returns the first day of the week containing d.
+func (m CalendarModel) startOfWeek(d time.Time) time.Time {
+	return stats.StartOfWeek(d, m.WeekStart)
+}
+
 func (m CalendarModel) Init() tea.Cmd {
 	return nil
 }
 
 func (m CalendarModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.diff.Width = msg.Width
+		m.diff.Height = msg.Height - 4
+		return m, nil
+
+	case dayCommitsMsg:
+		if !m.DayOpen || msg.date != m.SelectedDate.Format("2006-01-02") {
+			return m, nil // Stale response
+		}
+		m.dayCommits, m.dayErr, m.dayLoading = msg.commits, msg.err, false
+		return m, nil
+
+	case commitDetailMsg:
+		if msg.hash != m.diffHash {
+			return m, nil // Stale response
+		}
+		if msg.err != nil {
+			m.diff.SetContent("Error loading commit: " + msg.err.Error())
+		} else {
+			m.diff.SetContent(msg.detail)
+		}
+		m.diff.GotoTop()
+		return m, nil
+
 	case tea.KeyMsg:
+		if msg.String() == "ctrl+c" {
+			m.Quitting = true
+			return m, tea.Quit
+		}
+		if m.PickerOpen {
+			return m.updatePicker(msg)
+		}
+		if m.DiffOpen {
+			return m.updateDiff(msg)
+		}
+		if m.DayOpen {
+			return m.updateDay(msg)
+		}
+
 		switch msg.String() {
-		case "q", "ctrl+c", "esc":
+		case "q", "esc":
 			m.Quitting = true
 			return m, tea.Quit
 
+		case "enter": // Drill down into the selected day
+			key := m.SelectedDate.Format("2006-01-02")
+			if m.Activity.Count(key) > 0 {
+				m.DayOpen = true
+				m.DayCursor = 0
+				m.dayCommits, m.dayErr, m.dayLoading = nil, nil, true
+				return m, loadDay(m.SelectedDate, m.Activity[key], m.Bucketing)
+			}
+
+		case "a": // Pick an author to filter by
+			m.PickerOpen = true
+			m.pickerCursor = 0
+
+		case "b": // Toggle leaderboard panel
+			m.ShowLeaderboard = !m.ShowLeaderboard
+
+		case "f": // Toggle language/file-type panel
+			m.ShowLanguages = !m.ShowLanguages
+
+		case "m": // Cycle heat metric
+			m.SetMetric(stats.Metrics[(int(m.Metric)+1)%len(stats.Metrics)])
+
+		case "s": // Toggle summary panel
+			m.ShowSummary = !m.ShowSummary
+
+		case "v": // Toggle year/month layout
+			if m.Layout == CalendarLayoutYear {
+				m.Layout = CalendarLayoutMonth
+			} else {
+				m.Layout = CalendarLayoutYear
+			}
+
 		case "h", "left":
-			m.SelectedDate = m.SelectedDate.AddDate(0, 0, -1)
-			// Check if we moved back a month from the viewing window
-			if m.SelectedDate.Before(m.ViewingMonth) {
-				m.ViewingMonth = m.ViewingMonth.AddDate(0, -1, 0)
+			if m.Layout == CalendarLayoutYear {
+				m.move(-7) // Columns are weeks
+			} else {
+				m.move(-1)
 			}
 
 		case "l", "right":
-			newDate := m.SelectedDate.AddDate(0, 0, 1)
-			if !newDate.After(time.Now()) { // Prevent future selection
-				m.SelectedDate = newDate
-				// Check if we moved forward a month
-				nextMonth := m.ViewingMonth.AddDate(0, 1, 0)
-				if !m.SelectedDate.Before(nextMonth) {
-					m.ViewingMonth = nextMonth
-				}
+			if m.Layout == CalendarLayoutYear {
+				m.move(7)
+			} else {
+				m.move(1)
 			}
 
 		case "k", "up":
-			newDate := m.SelectedDate.AddDate(0, 0, -7)
-			if newDate.Before(m.ViewingMonth) {
-				// We moved to prev month visuals
-				m.ViewingMonth = m.ViewingMonth.AddDate(0, -1, 0)
+			if m.Layout == CalendarLayoutYear {
+				m.move(-1) // Rows are weekdays
+			} else {
+				m.move(-7)
 			}
-			m.SelectedDate = newDate
 
 		case "j", "down":
-			newDate := m.SelectedDate.AddDate(0, 0, 7)
-			if !newDate.After(time.Now()) {
-				m.SelectedDate = newDate
-				nextMonth := m.ViewingMonth.AddDate(0, 1, 0)
-				if !m.SelectedDate.Before(nextMonth) {
-					m.ViewingMonth = nextMonth
-				}
+			if m.Layout == CalendarLayoutYear {
+				m.move(1)
+			} else {
+				m.move(7)
 			}
 
 		case "[", "pgup": // Previous Month
-			prev := m.ViewingMonth.AddDate(0, -1, 0)
-			m.ViewingMonth = prev
-			// Adjust selection to stay in view? Or keep selection?
-			// Let's reset selection to 1st of that month
-			m.SelectedDate = prev
+			m.moveMonth(-1)
 
 		case "]", "pgdown": // Next Month
-			next := m.ViewingMonth.AddDate(0, 1, 0)
-			now := time.Now()
-			// Only allow if next month isn't totally in future (e.g. current month is fine)
-			if !next.After(time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, now.Location())) {
-				m.ViewingMonth = next
-				m.SelectedDate = next
-			}
+			m.moveMonth(1)
+
+		case "home":
+			m.SelectedDate = m.RangeStart
+			m.sync()
+
+		case "end":
+			m.SelectedDate = m.RangeEnd
+			m.sync()
+		}
+	}
+	return m, nil
+}
+
+// DayCommits returns the loaded commits of the selected day, newest first.
+func (m CalendarModel) DayCommits() []stats.Commit {
+	return m.dayCommits
+}
+
+func (m CalendarModel) updateDay(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	commits := m.DayCommits()
+	switch msg.String() {
+	case "q", "esc", "backspace":
+		m.DayOpen = false
+
+	case "up", "k":
+		if m.DayCursor > 0 {
+			m.DayCursor--
+		}
+
+	case "down", "j":
+		if m.DayCursor < len(commits)-1 {
+			m.DayCursor++
+		}
+
+	case "enter": // Open the diff of the highlighted commit
+		if m.DayCursor < len(commits) {
+			m.DiffOpen = true
+			m.diffHash = commits[m.DayCursor].Hash
+			m.diff.SetContent("Loading...")
+			return m, loadDetail(commits[m.DayCursor].Repo, m.diffHash)
 		}
 	}
 	return m, nil
 }
 
+func (m CalendarModel) updateDiff(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "q", "esc", "backspace":
+		m.DiffOpen = false
+		m.diffHash = ""
+		return m, nil
+	}
+	var cmd tea.Cmd
+	m.diff, cmd = m.diff.Update(msg)
+	return m, cmd
+}
+
 func (m CalendarModel) View() string {
 	if m.Quitting {
 		return ""
 	}
+	if m.DiffOpen {
+		return m.viewDiff()
+	}
+	if m.DayOpen {
+		return m.viewDay()
+	}
+	if m.PickerOpen {
+		return m.viewPicker()
+	}
+	if m.Layout == CalendarLayoutYear {
+		return m.viewYear() + m.viewSummary() + m.viewLanguages() + m.viewLeaderboard()
+	}
 
 	// Constants
 	// Box dimensions
@@ -114,12 +391,12 @@ func (m CalendarModel) View() string {
 		Foreground(lipgloss.Color("#38BDF8")). // Sky-400
 		Align(lipgloss.Center).
 		Width(50).
-		Render(m.ViewingMonth.Format("January 2006")) + "\n"
+		Render(m.ViewingMonth.Format("January 2006")+m.authorLabel()) + "\n"
 
 	// Weekday Headers
-	weekdays := []string{"Sun", "Mon", "Tue", "Wed", "Thu", "Fri", "Sat"}
 	wHeader := ""
-	for _, day := range weekdays {
+	for i := 0; i < 7; i++ {
+		day := time.Weekday((int(m.WeekStart) + i) % 7).String()[:3]
 		// Each box consumes ~7 chars width (including margin)
 		wHeader += lipgloss.NewStyle().Width(7).Align(lipgloss.Center).Bold(true).Render(day)
 	}
@@ -127,7 +404,7 @@ func (m CalendarModel) View() string {
 
 	// Grid Building
 	// Start day of week for 1st of month
-	startDay := int(m.ViewingMonth.Weekday()) // 0=Sun
+	startDay := stats.WeekdayIndex(m.ViewingMonth.Weekday(), m.WeekStart) // 0=first column
 	daysInMonth := daysIn(m.ViewingMonth.Month(), m.ViewingMonth.Year())
 
 	currentDayIdx := 1
@@ -154,13 +431,13 @@ func (m CalendarModel) View() string {
 			// Render Box
 			if isDay {
 				boxDate := time.Date(m.ViewingMonth.Year(), m.ViewingMonth.Month(), dayNum, 0, 0, 0, 0, m.ViewingMonth.Location())
-				count := m.Counts[boxDate.Format("2006-01-02")]
+				level := m.buckets.Level(m.Activity.Value(boxDate.Format("2006-01-02"), m.Metric))
 
 				isSelected := boxDate.Year() == m.SelectedDate.Year() &&
 					boxDate.Month() == m.SelectedDate.Month() &&
 					boxDate.Day() == m.SelectedDate.Day()
 
-				rowBlocks = append(rowBlocks, renderDayBox(dayNum, count, isSelected))
+				rowBlocks = append(rowBlocks, renderDayBox(dayNum, level, isSelected))
 			} else {
 				rowBlocks = append(rowBlocks, renderEmptyBox())
 			}
@@ -177,48 +454,99 @@ func (m CalendarModel) View() string {
 	help := lipgloss.NewStyle().
 		Foreground(lipgloss.Color("240")).
 		MarginTop(1).
-		Render("←/→/↑/↓: navigate  •  [/]: prev/next month  •  q: quit")
+		Render("←/→/↑/↓: navigate  •  [/]: prev/next month  •  enter: day commits  •  v: year view  •  m: metric  •  a: author  •  s: summary  •  f: languages  •  b: leaderboard  •  q: quit")
 
 	// Selected Info
 	selInfo := ""
 	if m.SelectedDate.IsZero() {
 		selInfo = " "
 	} else {
-		c := m.Counts[m.SelectedDate.Format("2006-01-02")]
 		selInfo = lipgloss.NewStyle().
 			Foreground(lipgloss.Color("#38BDF8")). // Sky-400
-			Render(fmt.Sprintf("%s: %d commits", m.SelectedDate.Format("Mon Jan 02"), c))
+			Render(fmt.Sprintf("%s: %s", m.SelectedDate.Format("Mon Jan 02"), m.selectedValue()))
 	}
 
 	return lipgloss.NewStyle().
 		Border(lipgloss.RoundedBorder()).
 		BorderForeground(lipgloss.Color("63")).
 		Padding(1, 2).
-		Render(header + "\n" + wHeader + gridStr + "\n" + selInfo + "\n" + help)
+		Render(header+"\n"+wHeader+gridStr+"\n"+selInfo+"\n"+help) + m.viewSummary() + m.viewLanguages() + m.viewLeaderboard()
 }
 
-func renderDayBox(day int, count int, selected bool) string {
-	// 1. Determine Colors
-	// Default: Empty Container
-	bgColor := lipgloss.Color("#262626") // Neutral Dark Grey (Distinct from Blue)
-	fgColor := lipgloss.Color("250")     // Grey Text
-
-	if count > 0 {
-		fgColor = lipgloss.Color("255") // White Text (Active)
-		if count <= 2 {
-			bgColor = lipgloss.Color("#1E3A5F") // Dark Blue
-		} else if count <= 5 {
-			bgColor = lipgloss.Color("#0369A1") // Sky-700
-		} else if count <= 10 {
-			bgColor = lipgloss.Color("#0EA5E9") // Sky-500
-		} else if count <= 15 {
-			bgColor = lipgloss.Color("#38BDF8") // Sky-400
-		} else {
-			bgColor = lipgloss.Color("#F59E0B") // Gold (Exceptional)
-			fgColor = lipgloss.Color("232")     // Black text on Gold
+func (m CalendarModel) viewDay() string {
+	commits := m.DayCommits()
+
+	header := lipgloss.NewStyle().
+		Bold(true).
+		Foreground(lipgloss.Color("#38BDF8")).
+		Render(fmt.Sprintf("%s: %d commits", m.SelectedDate.Format("Monday, January 02 2006"), m.Activity.Count(m.SelectedDate.Format("2006-01-02"))))
+
+	// Per-repository breakdown when aggregating several repositories
+	breakdown := m.Activity.ByRepo(m.SelectedDate.Format("2006-01-02"))
+	multiRepo := len(breakdown) > 1 || (len(breakdown) == 1 && breakdown[0].Repo != "")
+	if multiRepo {
+		var parts []string
+		for _, r := range breakdown {
+			parts = append(parts, fmt.Sprintf("%s: %d", repoName(r.Repo), r.Commits))
 		}
+		header += "\n" + lipgloss.NewStyle().Foreground(lipgloss.Color("244")).Render(strings.Join(parts, "  •  "))
 	}
 
+	var rows string
+	switch {
+	case m.dayLoading:
+		rows = "Loading...\n"
+	case m.dayErr != nil:
+		rows = "Error loading commits: " + m.dayErr.Error() + "\n"
+	}
+	for i, c := range commits {
+		cursor := "  "
+		subjStyle := lipgloss.NewStyle()
+		if i == m.DayCursor {
+			cursor = "> "
+			subjStyle = subjStyle.Bold(true).Underline(true)
+		}
+
+		hash := lipgloss.NewStyle().Foreground(lipgloss.Color("#F59E0B")).Render(c.ShortHash)
+		clock := lipgloss.NewStyle().Foreground(lipgloss.Color("#38BDF8")).Render(c.Time.Format("15:04"))
+		files := "file"
+		if c.FilesChanged != 1 {
+			files = "files"
+		}
+		meta := lipgloss.NewStyle().Foreground(lipgloss.Color("240")).
+			Render(fmt.Sprintf("%s • %d %s", c.Author, c.FilesChanged, files))
+
+		repo := ""
+		if multiRepo {
+			repo = lipgloss.NewStyle().Foreground(lipgloss.Color("#C084FC")).Render("["+repoName(c.Repo)+"]") + " "
+		}
+
+		rows += fmt.Sprintf("%s%s %s %s%s  %s\n", cursor, hash, clock, repo, subjStyle.Render(c.Subject), meta)
+	}
+
+	help := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("240")).
+		MarginTop(1).
+		Render("↑/↓: navigate  •  enter: show diff  •  esc: back to calendar")
+
+	return lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(lipgloss.Color("63")).
+		Padding(1, 2).
+		Render(header + "\n\n" + rows + help)
+}
+
+func (m CalendarModel) viewDiff() string {
+	help := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("240")).
+		Render("↑/↓/pgup/pgdn: scroll  •  esc: back to day")
+	return m.diff.View() + "\n" + help
+}
+
+func renderDayBox(day int, level int, selected bool) string {
+	// 1. Determine Colors
+	bgColor, fgColor := heatColors(level)
+
 	// 2. Base Style: UNIFORM 6x3 SOLID BLOCKS
 	// No borders. This guarantees identical dimensions for all squares.
 	style := lipgloss.NewStyle().
@@ -245,6 +573,19 @@ func renderDayBox(day int, count int, selected bool) string {
 	return style.Render(fmt.Sprintf("%02d", day))
 }
 
+// heatColors returns the background and text color for a heat level (see stats.Buckets).
+func heatColors(level int) (lipgloss.Color, lipgloss.Color) {
+	palette := stats.HeatPalette
+	switch {
+	case level <= 0:
+		return lipgloss.Color(palette[0]), lipgloss.Color("250") // Grey Text
+	case level >= len(palette)-1:
+		return lipgloss.Color(palette[len(palette)-1]), lipgloss.Color("232") // Black text on Gold
+	default:
+		return lipgloss.Color(palette[level]), lipgloss.Color("255") // White Text (Active)
+	}
+}
+
 func renderEmptyBox() string {
 	// Padding (Pre-month days)
 	return lipgloss.NewStyle().
@@ -255,6 +596,14 @@ func renderEmptyBox() string {
 		Render(" ")
 }
 
+// repoName is the display name of a repository directory.
+func repoName(dir string) string {
+	if dir == "" {
+		return "."
+	}
+	return filepath.Base(dir)
+}
+
 // Helper for days in month
 func daysIn(m time.Month, year int) int {
 	return time.Date(year, m+1, 0, 0, 0, 0, 0, time.UTC).Day()
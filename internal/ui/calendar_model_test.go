@@ -0,0 +1,181 @@
+package ui
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"raven/internal/stats"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestCalendarDayDrillDown(t *testing.T) {
+	today := time.Now().Format("2006-01-02")
+	activity := stats.Activity{
+		today: {{Author: "Alice", Email: "alice@example.com", Commits: 2}},
+	}
+	m := InitialCalendarModel(activity, time.Sunday)
+
+	next, cmd := m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	m = next.(CalendarModel)
+	if !m.DayOpen || cmd == nil || !strings.Contains(m.View(), "Loading...") {
+		t.Fatalf("expected Enter to open the day list and load its commits")
+	}
+
+	// Responses for another day are ignored
+	next, _ = m.Update(dayCommitsMsg{date: "2001-01-01", commits: []stats.Commit{{Hash: "old"}}})
+	m = next.(CalendarModel)
+	if len(m.DayCommits()) != 0 {
+		t.Fatalf("expected a stale response to be ignored")
+	}
+	next, _ = m.Update(dayCommitsMsg{date: today, commits: []stats.Commit{
+		{Hash: "bbb", ShortHash: "b", Subject: "fix: second"},
+		{Hash: "aaa", ShortHash: "a", Subject: "feat: first"},
+	}})
+	m = next.(CalendarModel)
+	if view := m.View(); !strings.Contains(view, "feat: first") || strings.Contains(view, "Loading...") {
+		t.Fatalf("expected the loaded commits to be listed:\n%s", view)
+	}
+
+	next, _ = m.Update(tea.KeyMsg{Type: tea.KeyDown})
+	m = next.(CalendarModel)
+	next, cmd = m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	m = next.(CalendarModel)
+	if !m.DiffOpen || m.diffHash != "aaa" || cmd == nil {
+		t.Fatalf("expected diff of second commit to load, got open=%v hash=%q", m.DiffOpen, m.diffHash)
+	}
+
+	next, _ = m.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	m = next.(CalendarModel)
+	next, _ = m.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	m = next.(CalendarModel)
+	if m.DayOpen || m.DiffOpen || m.Quitting {
+		t.Errorf("expected Esc twice to return to the calendar")
+	}
+}
+
+func TestCalendarYearNavigationClampsToRange(t *testing.T) {
+	m := InitialCalendarModel(stats.Activity{}, time.Sunday)
+	start := time.Date(2024, 1, 10, 0, 0, 0, 0, time.Local)
+	end := time.Date(2024, 3, 20, 0, 0, 0, 0, time.Local)
+	m.SetRange(start, end)
+
+	if m.Layout != CalendarLayoutYear {
+		t.Fatalf("expected year layout by default")
+	}
+	if !sameDay(m.SelectedDate, end) {
+		t.Fatalf("expected range end to be selected, got %v", m.SelectedDate)
+	}
+
+	// Right moves a week, but never past the range end
+	next, _ := m.Update(tea.KeyMsg{Type: tea.KeyRight})
+	m = next.(CalendarModel)
+	if !sameDay(m.SelectedDate, end) {
+		t.Errorf("expected selection to stay at range end, got %v", m.SelectedDate)
+	}
+
+	next, _ = m.Update(tea.KeyMsg{Type: tea.KeyLeft})
+	m = next.(CalendarModel)
+	if !sameDay(m.SelectedDate, end.AddDate(0, 0, -7)) {
+		t.Errorf("expected left to move one week back, got %v", m.SelectedDate)
+	}
+
+	// Jumping months stops at the range start
+	for i := 0; i < 5; i++ {
+		next, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("[")})
+		m = next.(CalendarModel)
+	}
+	if !sameDay(m.SelectedDate, start) {
+		t.Errorf("expected selection clamped to range start, got %v", m.SelectedDate)
+	}
+	if m.WindowStart.After(m.SelectedDate) {
+		t.Errorf("expected year window to contain the selection")
+	}
+}
+
+func TestCalendarAuthorPicker(t *testing.T) {
+	today := time.Now().Format("2006-01-02")
+	activity := stats.Activity{
+		today: {
+			{Author: "Alice", Email: "alice@example.com", Commits: 2},
+			{Author: "Bob", Email: "bob@example.com", Commits: 1},
+		},
+	}
+	m := InitialCalendarModel(activity, time.Sunday)
+
+	keys := []tea.KeyMsg{
+		{Type: tea.KeyRunes, Runes: []rune("a")},
+		{Type: tea.KeyDown}, // Alice (most commits)
+		{Type: tea.KeyDown}, // Bob
+		{Type: tea.KeyEnter},
+	}
+	for _, k := range keys {
+		next, _ := m.Update(k)
+		m = next.(CalendarModel)
+	}
+
+	if m.PickerOpen {
+		t.Fatalf("expected picker to close after selection")
+	}
+	if m.AuthorFilter != "bob@example.com" || m.Activity.Count(today) != 1 {
+		t.Errorf("expected only Bob's commit, got filter %q count %d", m.AuthorFilter, m.Activity.Count(today))
+	}
+	if m.AllActivity.Count(today) != 3 {
+		t.Errorf("expected unfiltered history to be kept")
+	}
+}
+
+func TestCalendarMetricSwitching(t *testing.T) {
+	today := time.Now().Format("2006-01-02")
+	m := InitialCalendarModel(stats.Activity{
+		today: {{Commits: 1, Additions: 120, Deletions: 20}},
+	}, time.Sunday)
+
+	if got := m.selectedValue(); got != "1 commits" {
+		t.Errorf("expected commit count by default, got %q", got)
+	}
+
+	next, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("m")})
+	m = next.(CalendarModel)
+	if m.Metric != stats.MetricAdditions || m.selectedValue() != "+120 lines" {
+		t.Errorf("expected lines added metric, got %v %q", m.Metric, m.selectedValue())
+	}
+	if level := m.buckets.Level(120); level == 0 {
+		t.Errorf("expected buckets rescaled to the additions data")
+	}
+}
+
+func TestCalendarDefaultRangeWeekStart(t *testing.T) {
+	for _, first := range []time.Weekday{time.Sunday, time.Monday} {
+		m := InitialCalendarModel(stats.Activity{}, first)
+		if m.RangeStart.Weekday() != first || m.WindowStart.Weekday() != first {
+			t.Errorf("week start %v: default range starts on %v, window on %v", first, m.RangeStart.Weekday(), m.WindowStart.Weekday())
+		}
+	}
+}
+
+func TestCalendarWeekStart(t *testing.T) {
+	m := InitialCalendarModel(stats.Activity{}, time.Sunday)
+	m.SetRange(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC), time.Date(2024, 3, 13, 0, 0, 0, 0, time.UTC))
+	m.SetWeekStart(time.Monday)
+
+	if m.WindowStart.Weekday() != time.Monday {
+		t.Errorf("expected year columns to start on Monday, got %v", m.WindowStart.Weekday())
+	}
+
+	// Down moves a day within the week column, Sunday ends the column
+	for i := 0; i < 4; i++ {
+		next, _ := m.Update(tea.KeyMsg{Type: tea.KeyUp})
+		m = next.(CalendarModel)
+	}
+	if m.SelectedDate.Weekday() != time.Saturday || m.startOfWeek(m.SelectedDate).Day() != 4 {
+		t.Errorf("expected Saturday Mar 9 in the week of Mar 4, got %v", m.SelectedDate)
+	}
+
+	m.Layout = CalendarLayoutMonth
+	view := m.View()
+	if mon, sun := strings.Index(view, "Mon"), strings.Index(view, "Sun"); mon < 0 || sun < mon {
+		t.Errorf("expected the month header to start with Mon")
+	}
+}
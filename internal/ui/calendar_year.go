@@ -0,0 +1,119 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"raven/internal/stats"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// viewYear renders the GitHub-style contribution graph:
+// one column per week, one row per weekday, one cell per day.
+func (m CalendarModel) viewYear() string {
+	weeks := m.yearWindowWeeks()
+	windowEnd := m.WindowStart.AddDate(0, 0, 7*weeks-1)
+
+	header := lipgloss.NewStyle().
+		Bold(true).
+		Foreground(lipgloss.Color("#38BDF8")). // Sky-400
+		Render(fmt.Sprintf("%s – %s%s", m.WindowStart.Format("Jan 2006"), windowEnd.Format("Jan 2006"), m.authorLabel()))
+
+	// Month labels: placed over the column in which the month starts
+	const gutter = "    "
+	labels := []rune(strings.Repeat(" ", weeks*2))
+	nextFree := 0
+	for w := 0; w < weeks; w++ {
+		weekStart := m.WindowStart.AddDate(0, 0, 7*w)
+		weekEnd := weekStart.AddDate(0, 0, 6)
+		if w > 0 && weekStart.Month() == weekEnd.Month() && weekStart.Day() > 1 {
+			continue
+		}
+		month := weekEnd.Month()
+		if w == 0 {
+			month = weekStart.Month()
+		}
+		name := []rune(month.String()[:3])
+		pos := w * 2
+		if pos < nextFree || pos+len(name) > len(labels) {
+			continue
+		}
+		copy(labels[pos:], name)
+		nextFree = pos + len(name) + 1
+	}
+	monthRow := lipgloss.NewStyle().Foreground(lipgloss.Color("244")).Render(gutter + string(labels))
+
+	// Grid: build rows by walking the days column by column
+	rows := make([]strings.Builder, 7)
+	for i := range rows {
+		name := ""
+		switch wd := time.Weekday((int(m.WeekStart) + i) % 7); wd {
+		case time.Monday, time.Wednesday, time.Friday:
+			name = wd.String()[:3]
+		}
+		rows[i].WriteString(lipgloss.NewStyle().Foreground(lipgloss.Color("244")).Width(len(gutter)).Render(name))
+	}
+	for i, day := range stats.Days(m.WindowStart, windowEnd) {
+		row := &rows[i%7]
+		if day.Before(m.RangeStart) || day.After(m.RangeEnd) {
+			row.WriteString("  ")
+			continue
+		}
+		level := m.buckets.Level(m.Activity.Value(day.Format("2006-01-02"), m.Metric))
+		row.WriteString(renderDayCell(level, sameDay(day, m.SelectedDate)) + " ")
+	}
+	var grid strings.Builder
+	for i := range rows {
+		grid.WriteString(rows[i].String() + "\n")
+	}
+
+	// Legend
+	legend := lipgloss.NewStyle().Foreground(lipgloss.Color("244")).Render("Less ")
+	for level := range stats.HeatPalette {
+		legend += renderDayCell(level, false) + " "
+	}
+	legend += lipgloss.NewStyle().Foreground(lipgloss.Color("244")).Render("More  (" + m.Metric.String() + ")")
+
+	// Selected Info
+	selInfo := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("#38BDF8")). // Sky-400
+		Render(fmt.Sprintf("%s: %s", m.SelectedDate.Format("Mon Jan 02 2006"), m.selectedValue()))
+
+	help := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("240")).
+		MarginTop(1).
+		Render("←/→: week  •  ↑/↓: day  •  [/]: month  •  enter: day commits  •  v: month view  •  m: metric  •  a: author  •  s: summary  •  f: languages  •  b: leaderboard  •  q: quit")
+
+	return lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(lipgloss.Color("63")).
+		Padding(1, 2).
+		Render(header + "\n\n" + monthRow + "\n" + grid.String() + "\n" + legend + "\n" + selInfo + "\n" + help)
+}
+
+// yearWindowWeeks is the number of week columns shown, fewer than a year for short ranges.
+func (m CalendarModel) yearWindowWeeks() int {
+	weeks := yearWeeks
+	if last := m.startOfWeek(m.RangeEnd); m.WindowStart.AddDate(0, 0, 7*(weeks-1)).After(last) {
+		weeks = int(last.Sub(m.WindowStart).Hours()/24/7+0.5) + 1
+	}
+	return weeks
+}
+
+// renderDayCell renders a single-character heatmap cell.
+func renderDayCell(level int, selected bool) string {
+	bgColor, _ := heatColors(level)
+	if selected {
+		return lipgloss.NewStyle().Foreground(lipgloss.Color("#FFFFFF")).Bold(true).Render("◆")
+	}
+	return lipgloss.NewStyle().Foreground(bgColor).Render("■")
+}
+
+// sameDay reports whether a and b fall on the same calendar day.
+func sameDay(a, b time.Time) bool {
+	y1, m1, d1 := a.Date()
+	y2, m2, d2 := b.Date()
+	return y1 == y2 && m1 == m2 && d1 == d2
+}
@@ -0,0 +1,139 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	"raven/internal/checks"
+
+	"github.com/charmbracelet/bubbles/spinner"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// checkDoneMsg carries the result of the check at index.
+type checkDoneMsg struct {
+	index  int
+	result checks.Result
+}
+
+// ChecksModel runs the pre-commit checks in parallel and shows their progress.
+// It quits once every check is done.
+type ChecksModel struct {
+	Checks   []checks.Check
+	Staged   checks.Staged
+	Results  []checks.Result
+	Canceled bool
+
+	done    []bool
+	spinner spinner.Model
+}
+
+// InitialChecksModel prepares the checks to run on the staged changes.
+func InitialChecksModel(list []checks.Check, staged checks.Staged) ChecksModel {
+	s := spinner.New()
+	s.Spinner = spinner.Dot
+	s.Style = lipgloss.NewStyle().Foreground(lipgloss.Color("#38BDF8"))
+	return ChecksModel{
+		Checks:  list,
+		Staged:  staged,
+		Results: make([]checks.Result, len(list)),
+		done:    make([]bool, len(list)),
+		spinner: s,
+	}
+}
+
+// Init starts every check at once.
+func (m ChecksModel) Init() tea.Cmd {
+	if len(m.Checks) == 0 {
+		return tea.Quit
+	}
+	cmds := []tea.Cmd{m.spinner.Tick}
+	for i, c := range m.Checks {
+		cmds = append(cmds, func() tea.Msg {
+			return checkDoneMsg{index: i, result: checks.Run(c, m.Staged)}
+		})
+	}
+	return tea.Batch(cmds...)
+}
+
+// Update records finished checks.
+func (m ChecksModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case checkDoneMsg:
+		m.Results[msg.index] = msg.result
+		m.done[msg.index] = true
+		if m.Done() {
+			return m, tea.Quit
+		}
+	case tea.KeyMsg:
+		if msg.String() == "ctrl+c" {
+			m.Canceled = true
+			return m, tea.Quit
+		}
+	case spinner.TickMsg:
+		var cmd tea.Cmd
+		m.spinner, cmd = m.spinner.Update(msg)
+		return m, cmd
+	}
+	return m, nil
+}
+
+// Done reports whether every check has finished.
+func (m ChecksModel) Done() bool {
+	for _, d := range m.done {
+		if !d {
+			return false
+		}
+	}
+	return true
+}
+
+// Failures returns the problems blocking the commit, one line each.
+func (m ChecksModel) Failures() []string {
+	var lines []string
+	for i, r := range m.Results {
+		if m.done[i] && r.Blocking() {
+			lines = append(lines, r.Lines()...)
+		}
+	}
+	return lines
+}
+
+// Warnings returns the problems of the checks that only warn, one line each.
+func (m ChecksModel) Warnings() []string {
+	var lines []string
+	for i, r := range m.Results {
+		if m.done[i] && r.Failed() && r.Warn {
+			lines = append(lines, r.Lines()...)
+		}
+	}
+	return lines
+}
+
+// View lists the checks: running, passed or failed.
+func (m ChecksModel) View() string {
+	pass := lipgloss.NewStyle().Foreground(lipgloss.Color("#34D399"))
+	fail := lipgloss.NewStyle().Foreground(lipgloss.Color("#F472B6")).Bold(true)
+	warn := lipgloss.NewStyle().Foreground(lipgloss.Color("#F59E0B")).Bold(true)
+	dim := lipgloss.NewStyle().Foreground(lipgloss.Color("240"))
+
+	var b strings.Builder
+	b.WriteString(lipgloss.NewStyle().Bold(true).Render(fmt.Sprintf("Checking %d staged file(s)", len(m.Staged.Files))) + "\n")
+	for i, c := range m.Checks {
+		r := m.Results[i]
+		switch {
+		case !m.done[i]:
+			b.WriteString("  " + m.spinner.View() + dim.Render(c.Name) + "\n")
+		case r.Failed() && r.Warn:
+			b.WriteString("  " + warn.Render("⚠ "+c.Name) + dim.Render(fmt.Sprintf("  %d warning(s)", len(r.Problems))) + "\n")
+		case r.Err != nil:
+			b.WriteString("  " + fail.Render("✘ "+c.Name) + dim.Render("  "+r.Err.Error()) + "\n")
+		case len(r.Problems) > 0:
+			b.WriteString("  " + fail.Render("✘ "+c.Name) + dim.Render(fmt.Sprintf("  %d problem(s)", len(r.Problems))) + "\n")
+		default:
+			b.WriteString("  " + pass.Render("✔ "+c.Name) + "\n")
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,46 @@
+package ui
+
+import (
+	"strings"
+	"testing"
+
+	"raven/internal/checks"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestChecksModelCollectsResults(t *testing.T) {
+	list := []checks.Check{
+		{Name: "whitespace", Run: func(checks.Staged) ([]checks.Problem, error) { return nil, nil }},
+		{Name: "gofmt", Run: func(checks.Staged) ([]checks.Problem, error) {
+			return []checks.Problem{{File: "main.go", Message: "not formatted"}}, nil
+		}},
+		{Name: "secrets", Warn: true, Run: func(checks.Staged) ([]checks.Problem, error) {
+			return []checks.Problem{{File: ".env", Message: "file likely holds credentials"}}, nil
+		}},
+	}
+	m := InitialChecksModel(list, checks.Staged{Files: []string{"main.go"}})
+	if !strings.Contains(m.View(), "Checking 1 staged file(s)") {
+		t.Errorf("unexpected view:\n%s", m.View())
+	}
+
+	// Results arrive in any order; the model quits after the last one.
+	var cmd tea.Cmd
+	for _, i := range []int{1, 2, 0} {
+		var next tea.Model
+		next, cmd = m.Update(checkDoneMsg{index: i, result: checks.Run(list[i], m.Staged)})
+		m = next.(ChecksModel)
+	}
+	if !m.Done() || cmd == nil {
+		t.Fatal("expected the model to be done and quit")
+	}
+	if got := m.Failures(); len(got) != 1 || got[0] != "gofmt: main.go: not formatted" {
+		t.Errorf("Failures() = %v", got)
+	}
+	if got := m.Warnings(); len(got) != 1 || got[0] != "secrets: .env: file likely holds credentials" {
+		t.Errorf("Warnings() = %v", got)
+	}
+	if view := m.View(); !strings.Contains(view, "✔ whitespace") || !strings.Contains(view, "✘ gofmt") || !strings.Contains(view, "⚠ secrets") {
+		t.Errorf("unexpected view:\n%s", view)
+	}
+}
@@ -0,0 +1,98 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	"raven/internal/analysis"
+	"raven/internal/git"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// FixupTarget is a commit the staged changes can be folded into.
+type FixupTarget struct {
+	Commit git.Message
+	Score  analysis.FixupScore
+}
+
+// FixupModel picks the commit a fixup! commit targets, best match first.
+type FixupModel struct {
+	Targets  []FixupTarget
+	Cursor   int
+	Chosen   int // Index of the chosen target, -1 if none
+	Quitting bool
+}
+
+// InitialFixupModel creates the picker over ranked targets.
+func InitialFixupModel(targets []FixupTarget) FixupModel {
+	return FixupModel{Targets: targets, Chosen: -1}
+}
+
+func (m FixupModel) Init() tea.Cmd {
+	return nil
+}
+
+func (m FixupModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	key, ok := msg.(tea.KeyMsg)
+	if !ok {
+		return m, nil
+	}
+	switch key.String() {
+	case "q", "esc", "ctrl+c":
+		m.Quitting = true
+		return m, tea.Quit
+	case "up", "k":
+		if m.Cursor > 0 {
+			m.Cursor--
+		}
+	case "down", "j":
+		if m.Cursor < len(m.Targets)-1 {
+			m.Cursor++
+		}
+	case "enter":
+		if m.Cursor < len(m.Targets) {
+			m.Chosen = m.Cursor
+			m.Quitting = true
+			return m, tea.Quit
+		}
+	}
+	return m, nil
+}
+
+func (m FixupModel) View() string {
+	if m.Quitting {
+		return ""
+	}
+
+	var s strings.Builder
+	s.WriteString(lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("#38BDF8")).Render("Fix up which commit?") + "\n\n")
+
+	dim := lipgloss.NewStyle().Foreground(lipgloss.Color("240"))
+	for i, t := range m.Targets {
+		cursor := "  "
+		style := lipgloss.NewStyle()
+		if i == m.Cursor {
+			cursor = "> "
+			style = style.Bold(true).Underline(true)
+		}
+		hash := lipgloss.NewStyle().Foreground(lipgloss.Color("#F59E0B")).Render(t.Commit.ShortHash)
+		subject, _, _ := strings.Cut(t.Commit.Text, "\n")
+		s.WriteString(cursor + hash + " " + style.Render(truncate(subject, 60)) + "  " + renderScore(t.Score) + "\n")
+	}
+	s.WriteString(dim.MarginTop(1).Render("↑/↓: navigate  •  enter: create fixup! commit  •  q: quit"))
+	return s.String()
+}
+
+// renderScore describes the overlap of a target with the staged changes.
+func renderScore(score analysis.FixupScore) string {
+	switch {
+	case score.Hunks > 0:
+		return lipgloss.NewStyle().Foreground(lipgloss.Color("#34D399")).
+			Render(fmt.Sprintf("%d hunk(s), %d file(s)", score.Hunks, score.Files))
+	case score.Files > 0:
+		return lipgloss.NewStyle().Foreground(lipgloss.Color("#F59E0B")).Render(fmt.Sprintf("%d file(s)", score.Files))
+	}
+	return lipgloss.NewStyle().Foreground(lipgloss.Color("240")).Render("no overlap")
+}
@@ -0,0 +1,29 @@
+package ui
+
+import (
+	"strings"
+	"testing"
+
+	"raven/internal/analysis"
+	"raven/internal/git"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestFixupPicker(t *testing.T) {
+	m := InitialFixupModel([]FixupTarget{
+		{Commit: git.Message{ShortHash: "b2", Text: "feat: b"}, Score: analysis.FixupScore{Files: 1, Hunks: 2}},
+		{Commit: git.Message{ShortHash: "a1", Text: "feat: a"}},
+	})
+	if view := m.View(); !strings.Contains(view, "2 hunk(s), 1 file(s)") || !strings.Contains(view, "no overlap") {
+		t.Errorf("expected the overlap of each target in the view, got:\n%s", view)
+	}
+
+	next, _ := m.Update(tea.KeyMsg{Type: tea.KeyDown})
+	m = next.(FixupModel)
+	next, cmd := m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	m = next.(FixupModel)
+	if m.Chosen != 1 || cmd == nil {
+		t.Errorf("expected the second target to be chosen, got %d", m.Chosen)
+	}
+}
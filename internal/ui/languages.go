@@ -0,0 +1,109 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"raven/internal/stats"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// languagesShown is the number of languages and directories listed in the panel.
+const languagesShown = 8
+
+// trendShown is the number of languages drawn in the monthly trend.
+const trendShown = 5
+
+// RenderLanguages renders the language share, the busiest directories and the
+// monthly language trend between start and end. It is used both by the stats
+// TUI and by `raven stats --languages`.
+func RenderLanguages(activity stats.Activity, start, end time.Time) string {
+	title := lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("#38BDF8"))
+	dim := lipgloss.NewStyle().Foreground(lipgloss.Color("240"))
+
+	var b strings.Builder
+	b.WriteString(title.Render(fmt.Sprintf("Languages %s – %s", start.Format("Jan 02 2006"), end.Format("Jan 02 2006"))) + "\n\n")
+
+	byLang := activity.ByLanguage(start, end)
+	if len(byLang) == 0 {
+		b.WriteString("No changed files in this range.")
+		return b.String()
+	}
+	writeShares(&b, byLang)
+
+	b.WriteString("\n" + title.Render("By directory") + "\n")
+	writeShares(&b, activity.ByDirectory(start, end))
+
+	// One sparkline column per month, showing each language's share of that month
+	trend := activity.LanguageTrend(start, end)
+	if len(trend) > 1 {
+		b.WriteString("\n" + title.Render("Monthly trend") + "\n")
+		bar := lipgloss.NewStyle().Foreground(lipgloss.Color("#0EA5E9"))
+		for i, share := range byLang {
+			if i == trendShown {
+				break
+			}
+			var spark strings.Builder
+			for _, month := range trend {
+				total := 0
+				for _, n := range month.Lines {
+					total += n
+				}
+				n := month.Lines[share.Name]
+				if n == 0 || total == 0 {
+					spark.WriteString(" ")
+					continue
+				}
+				spark.WriteRune(sparkBlocks[n*(len(sparkBlocks)-1)/total])
+			}
+			b.WriteString(fmt.Sprintf("%s %s\n", lipgloss.NewStyle().Width(16).Render(truncate(share.Name, 15)), bar.Render(spark.String())))
+		}
+		b.WriteString(dim.Render(fmt.Sprintf("%16s %s → %s", "", trend[0].Month.Format("Jan 2006"), trend[len(trend)-1].Month.Format("Jan 2006"))))
+	}
+	return strings.TrimSuffix(b.String(), "\n")
+}
+
+// writeShares writes one bar per share, scaled to its percentage of all changed lines.
+func writeShares(b *strings.Builder, shares []stats.Share) {
+	total := 0
+	for _, s := range shares {
+		total += s.Lines()
+	}
+	bar := lipgloss.NewStyle().Foreground(lipgloss.Color("#0EA5E9"))
+	dim := lipgloss.NewStyle().Foreground(lipgloss.Color("240"))
+	for i, s := range shares {
+		if i == languagesShown {
+			b.WriteString(dim.Render(fmt.Sprintf("… and %d more", len(shares)-languagesShown)) + "\n")
+			break
+		}
+		pct := 0
+		if total > 0 {
+			pct = s.Lines() * 100 / total
+		}
+		width := pct * 30 / 100
+		if s.Lines() > 0 && width == 0 {
+			width = 1
+		}
+		adds := lipgloss.NewStyle().Foreground(lipgloss.Color("#34D399")).Render(fmt.Sprintf("+%d", s.Additions))
+		dels := lipgloss.NewStyle().Foreground(lipgloss.Color("#F472B6")).Render(fmt.Sprintf("-%d", s.Deletions))
+		b.WriteString(fmt.Sprintf("%s %s %3d%%  %s %s %s\n",
+			lipgloss.NewStyle().Width(16).Render(truncate(s.Name, 15)),
+			bar.Render(fmt.Sprintf("%-30s", strings.Repeat("█", width))), pct, adds, dels,
+			dim.Render(fmt.Sprintf("(%d commits)", s.Commits))))
+	}
+}
+
+// viewLanguages renders the language panel for the visible range of the heatmap.
+func (m CalendarModel) viewLanguages() string {
+	if !m.ShowLanguages {
+		return ""
+	}
+	start, end := m.visibleRange()
+	return "\n" + lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(lipgloss.Color("240")).
+		Padding(0, 2).
+		Render(RenderLanguages(m.Activity, start, end))
+}
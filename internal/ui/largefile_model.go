@@ -0,0 +1,170 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	"raven/internal/checks"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// Actions for a large staged file.
+const (
+	LargeFileUnstage = "unstage" // Leave it out of the commit
+	LargeFileIgnore  = "ignore"  // Unstage it and add it to .gitignore
+	LargeFileLFS     = "lfs"     // Track it with git LFS
+	LargeFileKeep    = "keep"    // Commit it as is
+)
+
+// LargeFileItem is a large staged file and what to do with it ("" undecided).
+type LargeFileItem struct {
+	File   checks.LargeFile
+	Action string
+}
+
+// LargeFileModel is the warning screen listing the staged files over their
+// size limit. The user picks an action for each, then continues or aborts;
+// the caller carries the actions out.
+type LargeFileModel struct {
+	Items        []LargeFileItem
+	Cursor       int
+	LFSAvailable bool
+	Confirmed    bool
+	Message      string
+	Quitting     bool
+}
+
+// InitialLargeFileModel lists the large files, all undecided.
+func InitialLargeFileModel(files []checks.LargeFile, lfs bool) LargeFileModel {
+	items := make([]LargeFileItem, len(files))
+	for i, f := range files {
+		items[i] = LargeFileItem{File: f}
+	}
+	return LargeFileModel{Items: items, LFSAvailable: lfs}
+}
+
+func (m LargeFileModel) Init() tea.Cmd {
+	return nil
+}
+
+func (m LargeFileModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	key, ok := msg.(tea.KeyMsg)
+	if !ok {
+		return m, nil
+	}
+	m.Message = ""
+	switch key.String() {
+	case "q", "esc", "ctrl+c":
+		m.Quitting = true
+		return m, tea.Quit
+	case "up", "k":
+		if m.Cursor > 0 {
+			m.Cursor--
+		}
+	case "down", "j":
+		if m.Cursor < len(m.Items)-1 {
+			m.Cursor++
+		}
+	case "u":
+		m.set(LargeFileUnstage)
+	case "i":
+		m.set(LargeFileIgnore)
+	case "l":
+		if !m.LFSAvailable {
+			m.Message = "git LFS is not installed (https://git-lfs.com)."
+			return m, nil
+		}
+		m.set(LargeFileLFS)
+	case "a":
+		m.set(LargeFileKeep)
+	case "A":
+		for i := range m.Items {
+			if m.Items[i].Action == "" {
+				m.Items[i].Action = LargeFileKeep
+			}
+		}
+	case "enter":
+		for _, it := range m.Items {
+			if it.Action == "" {
+				m.Message = "Choose what to do with every file (a: commit it anyway)."
+				return m, nil
+			}
+		}
+		m.Confirmed = true
+		m.Quitting = true
+		return m, tea.Quit
+	}
+	return m, nil
+}
+
+// set gives the highlighted file an action, or clears it if it had it
+// already, and moves to the next file.
+func (m *LargeFileModel) set(action string) {
+	if m.Cursor >= len(m.Items) {
+		return
+	}
+	it := &m.Items[m.Cursor]
+	if it.Action == action {
+		it.Action = ""
+		return
+	}
+	it.Action = action
+	if m.Cursor < len(m.Items)-1 {
+		m.Cursor++
+	}
+}
+
+// DescribeLargeFileAction explains what an action does with a file.
+func DescribeLargeFileAction(it LargeFileItem) string {
+	switch it.Action {
+	case LargeFileUnstage:
+		return "unstage"
+	case LargeFileIgnore:
+		return "unstage, add /" + it.File.Path + " to .gitignore"
+	case LargeFileLFS:
+		return "track " + checks.LFSPattern(it.File.Path) + " with git LFS"
+	case LargeFileKeep:
+		return "commit anyway"
+	}
+	return "?"
+}
+
+func (m LargeFileModel) View() string {
+	if m.Quitting {
+		return ""
+	}
+	warn := lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("#F472B6"))
+	dim := lipgloss.NewStyle().Foreground(lipgloss.Color("240"))
+	gold := lipgloss.NewStyle().Foreground(lipgloss.Color("#F59E0B"))
+
+	var s strings.Builder
+	s.WriteString(warn.Render(fmt.Sprintf("⚠ %d large file(s) staged", len(m.Items))) + "\n")
+	s.WriteString(dim.Render("Every version of a large or binary file stays in the history for good.") + "\n\n")
+
+	for i, it := range m.Items {
+		cursor := "  "
+		style := lipgloss.NewStyle()
+		if i == m.Cursor {
+			cursor = "> "
+			style = style.Bold(true).Underline(true)
+		}
+		action := dim.Render("choose an action")
+		if it.Action != "" {
+			action = lipgloss.NewStyle().Foreground(lipgloss.Color("#38BDF8")).Render("→ " + DescribeLargeFileAction(it))
+		}
+		s.WriteString(cursor + style.Render(truncate(it.File.Path, 50)) + "  " + gold.Render(it.File.Reason()) + "  " + action + "\n")
+	}
+
+	if m.Message != "" {
+		s.WriteString("\n" + warn.Render(m.Message) + "\n")
+	}
+	help := []string{"u: unstage", "i: .gitignore"}
+	if m.LFSAvailable {
+		help = append(help, "l: git LFS")
+	}
+	help = append(help, "a/A: commit anyway (all)", "enter: continue", "q: abort")
+	s.WriteString(lipgloss.NewStyle().Foreground(lipgloss.Color("240")).MarginTop(1).Render(strings.Join(help, "  •  ")))
+	return s.String()
+}
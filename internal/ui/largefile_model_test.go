@@ -0,0 +1,53 @@
+package ui
+
+import (
+	"testing"
+
+	"raven/internal/checks"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestLargeFileActions(t *testing.T) {
+	m := InitialLargeFileModel([]checks.LargeFile{
+		{Path: "dist/app.zip", Size: 200 << 20, Binary: true, Limit: 1 << 20},
+		{Path: "data/dump.sql", Size: 8 << 20, Limit: 5 << 20},
+		{Path: "assets/logo.psd", Size: 3 << 20, Binary: true, Limit: 1 << 20},
+	}, false)
+
+	press := func(msgs ...tea.Msg) tea.Cmd {
+		var cmd tea.Cmd
+		for _, msg := range msgs {
+			var next tea.Model
+			next, cmd = m.Update(msg)
+			m = next.(LargeFileModel)
+		}
+		return cmd
+	}
+
+	// Without git LFS, l is refused and the cursor stays.
+	press(runes("l"))
+	if m.Items[0].Action != "" || m.Message == "" {
+		t.Fatalf("expected LFS to be refused: %+v", m.Items[0])
+	}
+
+	// An action moves to the next file; enter needs every file decided.
+	press(runes("i"), runes("u"))
+	if cmd := press(tea.KeyMsg{Type: tea.KeyEnter}); cmd != nil || m.Confirmed {
+		t.Fatal("expected enter to wait for the last file")
+	}
+	press(runes("A"))
+	if cmd := press(tea.KeyMsg{Type: tea.KeyEnter}); cmd == nil || !m.Confirmed {
+		t.Fatal("expected enter to continue")
+	}
+
+	want := []string{LargeFileIgnore, LargeFileUnstage, LargeFileKeep}
+	for i, it := range m.Items {
+		if it.Action != want[i] {
+			t.Errorf("Items[%d].Action = %q, want %q", i, it.Action, want[i])
+		}
+	}
+	if got := DescribeLargeFileAction(m.Items[0]); got != "unstage, add /dist/app.zip to .gitignore" {
+		t.Errorf("DescribeLargeFileAction() = %q", got)
+	}
+}
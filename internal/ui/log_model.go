@@ -0,0 +1,389 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"raven/internal/analysis"
+	"raven/internal/git"
+
+	"github.com/charmbracelet/bubbles/viewport"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// LogTypes is the cycle order for the in-TUI type filter ("" = all).
+var LogTypes = []string{"", "feat", "fix", "docs", "refactor", "perf", "test", "build", "ci", "chore", "style", "revert"}
+
+// typeColors maps Conventional Commit types to badge colors.
+var typeColors = map[string]lipgloss.Color{
+	"feat":     lipgloss.Color("#38BDF8"), // Sky Blue
+	"fix":      lipgloss.Color("#F472B6"), // Pink
+	"docs":     lipgloss.Color("#A3E635"), // Lime
+	"refactor": lipgloss.Color("#C084FC"), // Purple
+	"perf":     lipgloss.Color("#F59E0B"), // Gold
+	"test":     lipgloss.Color("#34D399"), // Green
+	"chore":    lipgloss.Color("#9CA3AF"), // Grey
+}
+
+type logLoadedMsg struct {
+	entries []git.LogEntry
+	err     error
+}
+
+type commitDetailMsg struct {
+	hash   string
+	detail string
+	err    error
+}
+
+// LogModel is the interactive commit history browser.
+type LogModel struct {
+	Entries     []git.LogEntry
+	Cursor      int // Index into Entries, always points at a commit line
+	Options     git.LogOptions
+	TypeFilter  string
+	ScopeFilter string
+	Err         error
+	Quitting    bool
+
+	detail      viewport.Model
+	detailHash  string
+	focusDetail bool
+	offset      int
+	width       int
+	height      int
+}
+
+// InitialLogModel creates the log browser. Entries are loaded in Init.
+func InitialLogModel(opts git.LogOptions, typeFilter, scopeFilter string) LogModel {
+	opts.Graph = true
+	return LogModel{
+		Options:     opts,
+		TypeFilter:  typeFilter,
+		ScopeFilter: scopeFilter,
+		detail:      viewport.New(80, 10),
+		width:       80,
+		height:      24,
+	}
+}
+
+// Query returns the git log options. The type/scope filters match parsed
+// subjects after loading (see Filter), so while one is set the graph is left
+// out (its edges would not connect) and the limit is applied by Filter.
+func (m LogModel) Query() git.LogOptions {
+	opts := m.Options
+	if m.TypeFilter != "" || m.ScopeFilter != "" {
+		opts.Graph = false
+		opts.Max = 0
+	}
+	return opts
+}
+
+// Filter keeps the commits whose subject is a Conventional header matching
+// the type and scope filters.
+func (m LogModel) Filter(entries []git.LogEntry) []git.LogEntry {
+	if m.TypeFilter == "" && m.ScopeFilter == "" {
+		return entries
+	}
+	var kept []git.LogEntry
+	for _, e := range entries {
+		if e.Commit == nil {
+			continue
+		}
+		h, ok := analysis.ParseHeader(e.Commit.Subject)
+		if !ok || (m.TypeFilter != "" && h.Type != strings.ToLower(m.TypeFilter)) || (m.ScopeFilter != "" && h.Scope != m.ScopeFilter) {
+			continue
+		}
+		kept = append(kept, e)
+		if m.Options.Max > 0 && len(kept) == m.Options.Max {
+			break
+		}
+	}
+	return kept
+}
+
+func (m LogModel) load() tea.Cmd {
+	opts := m.Query()
+	return func() tea.Msg {
+		entries, err := git.GetLog(opts)
+		return logLoadedMsg{entries: m.Filter(entries), err: err}
+	}
+}
+
+// loadDetail shows a commit of the repository at dir ("" = current directory).
+func loadDetail(dir, hash string) tea.Cmd {
+	return func() tea.Msg {
+		detail, err := git.ShowCommitIn(dir, hash)
+		return commitDetailMsg{hash: hash, detail: detail, err: err}
+	}
+}
+
+func (m LogModel) Init() tea.Cmd {
+	return m.load()
+}
+
+// Selected returns the highlighted commit, or nil if the list is empty.
+func (m LogModel) Selected() *git.Commit {
+	if m.Cursor < 0 || m.Cursor >= len(m.Entries) {
+		return nil
+	}
+	return m.Entries[m.Cursor].Commit
+}
+
+func (m LogModel) listHeight() int {
+	h := (m.height - 6) / 2
+	if h < 3 {
+		h = 3
+	}
+	return h
+}
+
+func (m LogModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.width = msg.Width
+		m.height = msg.Height
+		m.detail.Width = msg.Width
+		m.detail.Height = msg.Height - m.listHeight() - 5
+		return m, nil
+
+	case logLoadedMsg:
+		m.Err = msg.err
+		m.Entries = msg.entries
+		m.Cursor = m.nextCommit(-1, 1)
+		m.offset = 0
+		m.detailHash = ""
+		m.detail.SetContent("")
+		return m, m.syncDetail()
+
+	case commitDetailMsg:
+		if msg.hash != m.detailHash {
+			return m, nil // Stale response
+		}
+		if msg.err != nil {
+			m.detail.SetContent("Error loading commit: " + msg.err.Error())
+		} else {
+			m.detail.SetContent(msg.detail)
+		}
+		m.detail.GotoTop()
+		return m, nil
+
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "q", "ctrl+c", "esc":
+			m.Quitting = true
+			return m, tea.Quit
+
+		case "tab", "enter":
+			m.focusDetail = !m.focusDetail
+			return m, nil
+
+		case "t": // Cycle type filter
+			m.TypeFilter = nextType(m.TypeFilter)
+			return m, m.load()
+		}
+
+		if m.focusDetail {
+			var cmd tea.Cmd
+			m.detail, cmd = m.detail.Update(msg)
+			return m, cmd
+		}
+
+		switch msg.String() {
+		case "up", "k":
+			if i := m.nextCommit(m.Cursor, -1); i >= 0 {
+				m.Cursor = i
+			}
+		case "down", "j":
+			if i := m.nextCommit(m.Cursor, 1); i >= 0 {
+				m.Cursor = i
+			}
+		case "g", "home":
+			m.Cursor = m.nextCommit(-1, 1)
+		case "G", "end":
+			m.Cursor = m.nextCommit(len(m.Entries), -1)
+		}
+		m.scroll()
+		return m, m.syncDetail()
+	}
+	return m, nil
+}
+
+// nextCommit finds the next entry from i in direction dir that is a commit.
+func (m LogModel) nextCommit(i, dir int) int {
+	for i += dir; i >= 0 && i < len(m.Entries); i += dir {
+		if m.Entries[i].Commit != nil {
+			return i
+		}
+	}
+	return -1
+}
+
+func (m *LogModel) scroll() {
+	h := m.listHeight()
+	if m.Cursor < m.offset {
+		m.offset = m.Cursor
+	} else if m.Cursor >= m.offset+h {
+		m.offset = m.Cursor - h + 1
+	}
+}
+
+// syncDetail requests the diff for the highlighted commit if it changed.
+func (m *LogModel) syncDetail() tea.Cmd {
+	c := m.Selected()
+	if c == nil || c.Hash == m.detailHash {
+		return nil
+	}
+	m.detailHash = c.Hash
+	m.detail.SetContent("Loading...")
+	return loadDetail("", c.Hash)
+}
+
+func nextType(current string) string {
+	for i, t := range LogTypes {
+		if t == current {
+			return LogTypes[(i+1)%len(LogTypes)]
+		}
+	}
+	return LogTypes[0]
+}
+
+func (m LogModel) View() string {
+	if m.Quitting {
+		return ""
+	}
+
+	var s strings.Builder
+
+	// 1. Header with active filters
+	title := "Commit History"
+	var filters []string
+	if m.TypeFilter != "" {
+		filters = append(filters, "type:"+m.TypeFilter)
+	}
+	if m.ScopeFilter != "" {
+		filters = append(filters, "scope:"+m.ScopeFilter)
+	}
+	if m.Options.Author != "" {
+		filters = append(filters, "author:"+m.Options.Author)
+	}
+	if len(m.Options.Paths) > 0 {
+		filters = append(filters, "path:"+strings.Join(m.Options.Paths, ","))
+	}
+	if m.Options.Since != "" {
+		filters = append(filters, "since:"+m.Options.Since)
+	}
+	if m.Options.Until != "" {
+		filters = append(filters, "until:"+m.Options.Until)
+	}
+	if len(filters) > 0 {
+		title += "  [" + strings.Join(filters, " ") + "]"
+	}
+	s.WriteString(lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("#38BDF8")).Render(title) + "\n\n")
+
+	// 2. Commit List
+	if m.Err != nil {
+		s.WriteString(lipgloss.NewStyle().Foreground(lipgloss.Color("#F472B6")).Render("Error reading history: "+m.Err.Error()) + "\n")
+	} else if len(m.Entries) == 0 {
+		s.WriteString("No commits match the current filters.\n")
+	}
+
+	end := m.offset + m.listHeight()
+	if end > len(m.Entries) {
+		end = len(m.Entries)
+	}
+	for i := m.offset; i < end; i++ {
+		s.WriteString(m.renderEntry(i) + "\n")
+	}
+
+	// 3. Detail Pane
+	sepColor := lipgloss.Color("240")
+	if m.focusDetail {
+		sepColor = lipgloss.Color("63")
+	}
+	s.WriteString(lipgloss.NewStyle().Foreground(sepColor).Render(strings.Repeat("─", m.width)) + "\n")
+	s.WriteString(m.detail.View() + "\n")
+
+	// 4. Help
+	help := "↑/↓: navigate  •  tab: scroll diff  •  t: cycle type  •  q: quit"
+	if m.focusDetail {
+		help = "↑/↓/pgup/pgdn: scroll diff  •  tab: back to list  •  q: quit"
+	}
+	s.WriteString(lipgloss.NewStyle().Foreground(lipgloss.Color("240")).Render(help))
+
+	return s.String()
+}
+
+func (m LogModel) renderEntry(i int) string {
+	e := m.Entries[i]
+	graph := lipgloss.NewStyle().Foreground(lipgloss.Color("63")).Render(e.Graph)
+	if e.Commit == nil {
+		return "  " + graph
+	}
+	c := e.Commit
+
+	cursor := "  "
+	if i == m.Cursor {
+		cursor = "> "
+	}
+
+	hash := lipgloss.NewStyle().Foreground(lipgloss.Color("#F59E0B")).Render(c.ShortHash)
+
+	subject := c.Subject
+	badge := ""
+	if h, ok := analysis.ParseHeader(c.Subject); ok {
+		badge = renderTypeBadge(h) + " "
+		subject = h.Description
+	}
+
+	subjStyle := lipgloss.NewStyle()
+	if i == m.Cursor {
+		subjStyle = subjStyle.Bold(true).Underline(true)
+	}
+
+	meta := lipgloss.NewStyle().Foreground(lipgloss.Color("240")).
+		Render(fmt.Sprintf("%s, %s", c.Author, relativeAge(c.Date)))
+
+	return fmt.Sprintf("%s%s %s %s%s  %s", cursor, graph, hash, badge, subjStyle.Render(subject), meta)
+}
+
+// renderTypeBadge renders "type(scope)" as a colored badge.
+func renderTypeBadge(h analysis.Header) string {
+	color, ok := typeColors[h.Type]
+	if !ok {
+		color = lipgloss.Color("250")
+	}
+	label := h.Type
+	if h.Scope != "" {
+		label += ":" + h.Scope
+	}
+	if h.Breaking {
+		label += "!"
+	}
+	return lipgloss.NewStyle().
+		Foreground(lipgloss.Color("232")).
+		Background(color).
+		Padding(0, 1).
+		Render(label)
+}
+
+// relativeAge formats how long ago t was, e.g. "3d ago".
+func relativeAge(t time.Time) string {
+	d := time.Since(t)
+	switch {
+	case d < time.Minute:
+		return "just now"
+	case d < time.Hour:
+		return fmt.Sprintf("%dm ago", int(d.Minutes()))
+	case d < 24*time.Hour:
+		return fmt.Sprintf("%dh ago", int(d.Hours()))
+	case d < 30*24*time.Hour:
+		return fmt.Sprintf("%dd ago", int(d.Hours()/24))
+	case d < 365*24*time.Hour:
+		return fmt.Sprintf("%dmo ago", int(d.Hours()/24/30))
+	default:
+		return fmt.Sprintf("%dy ago", int(d.Hours()/24/365))
+	}
+}
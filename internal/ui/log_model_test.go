@@ -0,0 +1,115 @@
+package ui
+
+import (
+	"testing"
+
+	"raven/internal/git"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func logEntries(subjects ...string) []git.LogEntry {
+	var entries []git.LogEntry
+	for i, s := range subjects {
+		if s == "" {
+			entries = append(entries, git.LogEntry{Graph: "|\\"})
+			continue
+		}
+		hash := string(rune('a' + i))
+		entries = append(entries, git.LogEntry{Graph: "* ", Commit: &git.Commit{Hash: hash, ShortHash: hash, Subject: s}})
+	}
+	return entries
+}
+
+func TestLogModelFilter(t *testing.T) {
+	entries := logEntries(
+		"feat(ui): add log",
+		"",
+		"fix(ui): crash\n",
+		"fix: unscoped",
+		"Merge branch 'fix/x'",
+		"chore: bump\n\nfix(ui): mentioned in a body line",
+		"FIX(ui): shouting",
+	)
+
+	tests := []struct {
+		typ, scope string
+		want       []string
+	}{
+		{"fix", "", []string{"c", "d", "g"}},
+		{"fix", "ui", []string{"c", "g"}},
+		{"", "ui", []string{"a", "c", "g"}},
+		{"chore", "", []string{"f"}},
+	}
+	for _, tt := range tests {
+		m := InitialLogModel(git.LogOptions{}, tt.typ, tt.scope)
+		if q := m.Query(); q.Graph {
+			t.Errorf("type %q scope %q: expected no graph while filtering", tt.typ, tt.scope)
+		}
+		var got []string
+		for _, e := range m.Filter(entries) {
+			got = append(got, e.Commit.Hash)
+		}
+		if len(got) != len(tt.want) {
+			t.Errorf("type %q scope %q: got %v, want %v", tt.typ, tt.scope, got, tt.want)
+			continue
+		}
+		for i := range got {
+			if got[i] != tt.want[i] {
+				t.Errorf("type %q scope %q: got %v, want %v", tt.typ, tt.scope, got, tt.want)
+				break
+			}
+		}
+	}
+
+	m := InitialLogModel(git.LogOptions{Max: 1}, "fix", "")
+	if q := m.Query(); q.Max != 0 {
+		t.Errorf("expected the limit to be applied after filtering, got Max %d", q.Max)
+	}
+	if got := m.Filter(entries); len(got) != 1 || got[0].Commit.Hash != "c" {
+		t.Errorf("expected only the first fix, got %+v", got)
+	}
+
+	unfiltered := InitialLogModel(git.LogOptions{}, "", "")
+	if !unfiltered.Query().Graph || len(unfiltered.Filter(entries)) != len(entries) {
+		t.Errorf("expected the graph and every entry without filters")
+	}
+}
+
+func TestLogModelNavigation(t *testing.T) {
+	m := InitialLogModel(git.LogOptions{}, "", "")
+	next, _ := m.Update(logLoadedMsg{entries: append(logEntries("", "feat: a", "", "fix: b"), logEntries("docs: c")...)})
+	m = next.(LogModel)
+	if m.Cursor != 1 || m.Selected().Subject != "feat: a" {
+		t.Fatalf("expected the first commit selected, got cursor %d", m.Cursor)
+	}
+
+	press := func(key tea.KeyMsg) tea.Cmd {
+		next, cmd := m.Update(key)
+		m = next.(LogModel)
+		return cmd
+	}
+	if cmd := press(tea.KeyMsg{Type: tea.KeyDown}); m.Cursor != 3 || cmd == nil {
+		t.Errorf("expected down to skip the graph line and load the diff, got cursor %d", m.Cursor)
+	}
+	press(tea.KeyMsg{Type: tea.KeyEnd})
+	if m.Selected().Subject != "docs: c" {
+		t.Errorf("expected end to select the last commit, got %q", m.Selected().Subject)
+	}
+	press(tea.KeyMsg{Type: tea.KeyDown})
+	if m.Selected().Subject != "docs: c" {
+		t.Errorf("expected down to stop at the last commit")
+	}
+	press(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("g")})
+	if m.Cursor != 1 {
+		t.Errorf("expected g to return to the first commit, got %d", m.Cursor)
+	}
+
+	if cmd := press(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("t")}); m.TypeFilter != "feat" || cmd == nil {
+		t.Errorf("expected t to filter by feat and reload, got %q", m.TypeFilter)
+	}
+	press(tea.KeyMsg{Type: tea.KeyTab})
+	if !m.focusDetail {
+		t.Errorf("expected tab to focus the detail pane")
+	}
+}
@@ -2,6 +2,7 @@ package ui
 
 import (
 	"fmt"
+	"strings"
 
 	"github.com/charmbracelet/bubbles/textinput"
 	tea "github.com/charmbracelet/bubbletea"
@@ -18,6 +19,14 @@ const (
 	ChoiceCancel
 )
 
+// IssueRef is an issue that can be referenced by a trailer of the commit.
+type IssueRef struct {
+	Key      string // "ABC-42" or "#123"
+	Trailer  string // "Refs", "Closes", ...
+	Source   string // Where it was found: "branch", "recent commit" or "typed"
+	Selected bool
+}
+
 // Model represents the state of the UI.
 type Model struct {
 	Message  string
@@ -26,6 +35,24 @@ type Model struct {
 	cursor   int
 	choices  []string
 
+	// Trailer lines appended below the message, e.g. "Signed-off-by: ..."
+	Trailers []string
+
+	// Issue references; the selected ones are appended as "Trailer: Key".
+	// TrailerKeys are the trailers an issue can be cycled through.
+	Issues        []IssueRef
+	TrailerKeys   []string
+	PickingIssues bool
+	AddingIssue   bool
+	IssueInput    textinput.Model
+	issueCursor   int
+
+	// Problems found by the pre-commit checks, one per line. Apply is refused
+	// while there are any; --no-verify skips the checks.
+	CheckFailures []string
+	CheckWarnings []string // Reported without blocking
+	checksBlocked bool
+
 	// Inline Editing State
 	IsEditing bool
 	Input     textinput.Model
@@ -39,13 +66,40 @@ func InitialModel(msg string) Model {
 	ti.Width = 60
 	ti.CharLimit = 100
 
+	ii := textinput.New()
+	ii.Placeholder = "ABC-42 or #123"
+	ii.Width = 30
+	ii.CharLimit = 40
+
 	return Model{
-		Message: msg,
-		Choice:  ChoiceNone,
-		choices: []string{"Apply", "Edit", "Cancel"},
-		cursor:  0,
-		Input:   ti,
+		Message:     msg,
+		Choice:      ChoiceNone,
+		choices:     []string{"Apply", "Edit", "Issues", "Cancel"},
+		cursor:      0,
+		Input:       ti,
+		IssueInput:  ii,
+		TrailerKeys: []string{"Refs", "Closes"},
+	}
+}
+
+// AllTrailers returns the fixed trailers followed by the selected issue references.
+func (m Model) AllTrailers() []string {
+	trailers := append([]string(nil), m.Trailers...)
+	for _, issue := range m.Issues {
+		if issue.Selected {
+			trailers = append(trailers, issue.Trailer+": "+issue.Key)
+		}
 	}
+	return trailers
+}
+
+// FullMessage returns the message followed by its trailers.
+func (m Model) FullMessage() string {
+	trailers := m.AllTrailers()
+	if len(trailers) == 0 {
+		return m.Message
+	}
+	return m.Message + "\n\n" + strings.Join(trailers, "\n")
 }
 
 // Init initializes the IO.
@@ -80,6 +134,10 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		return m, cmd
 	}
 
+	if m.PickingIssues {
+		return m.updateIssues(msg)
+	}
+
 	// NORMAL NAVIGATION MODE
 	switch msg := msg.(type) {
 	case tea.KeyMsg:
@@ -89,6 +147,10 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			m.Quitting = true
 			return m, tea.Quit
 
+		case "i":
+			m.PickingIssues = true
+			return m, nil
+
 		case "up", "k", "left", "h":
 			if m.cursor > 0 {
 				m.cursor--
@@ -107,6 +169,10 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			// Map cursor to Choice
 			switch m.cursor {
 			case 0:
+				if len(m.CheckFailures) > 0 {
+					m.checksBlocked = true
+					return m, nil
+				}
 				m.Choice = ChoiceApply
 				m.Quitting = true
 				return m, tea.Quit
@@ -118,6 +184,9 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				m.Input.Focus()
 				return m, textinput.Blink
 			case 2:
+				m.PickingIssues = true
+				return m, nil
+			case 3:
 				m.Choice = ChoiceCancel
 				m.Quitting = true
 				return m, tea.Quit
@@ -127,6 +196,165 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	return m, nil
 }
 
+// updateIssues handles keys while picking issue references.
+func (m Model) updateIssues(msg tea.Msg) (tea.Model, tea.Cmd) {
+	if m.AddingIssue {
+		var cmd tea.Cmd
+		if key, ok := msg.(tea.KeyMsg); ok {
+			switch key.Type {
+			case tea.KeyEnter:
+				m.addIssue(m.IssueInput.Value())
+				m.AddingIssue = false
+				m.IssueInput.Blur()
+				return m, nil
+			case tea.KeyEsc:
+				m.AddingIssue = false
+				m.IssueInput.Blur()
+				return m, nil
+			}
+		}
+		m.IssueInput, cmd = m.IssueInput.Update(msg)
+		return m, cmd
+	}
+
+	key, ok := msg.(tea.KeyMsg)
+	if !ok {
+		return m, nil
+	}
+	switch key.String() {
+	case "ctrl+c":
+		m.Choice = ChoiceCancel
+		m.Quitting = true
+		return m, tea.Quit
+	case "enter", "esc", "q", "i":
+		m.PickingIssues = false
+	case "up", "k":
+		if m.issueCursor > 0 {
+			m.issueCursor--
+		}
+	case "down", "j":
+		if m.issueCursor < len(m.Issues)-1 {
+			m.issueCursor++
+		}
+	case " ", "x":
+		if m.issueCursor < len(m.Issues) {
+			m.Issues[m.issueCursor].Selected = !m.Issues[m.issueCursor].Selected
+		}
+	case "t":
+		if m.issueCursor < len(m.Issues) && len(m.TrailerKeys) > 0 {
+			issue := &m.Issues[m.issueCursor]
+			issue.Trailer = m.TrailerKeys[(indexOf(m.TrailerKeys, issue.Trailer)+1)%len(m.TrailerKeys)]
+			issue.Selected = true
+		}
+	case "a":
+		m.AddingIssue = true
+		m.IssueInput.SetValue("")
+		m.IssueInput.Focus()
+		return m, textinput.Blink
+	}
+	return m, nil
+}
+
+// addIssue adds a typed issue key, selected. A bare number becomes "#N".
+// Typing a key that is already listed selects it instead.
+func (m *Model) addIssue(key string) {
+	key = strings.TrimSpace(key)
+	if key == "" {
+		return
+	}
+	if strings.Trim(key, "0123456789") == "" {
+		key = "#" + key
+	}
+	for i := range m.Issues {
+		if strings.EqualFold(m.Issues[i].Key, key) {
+			m.Issues[i].Selected = true
+			m.issueCursor = i
+			return
+		}
+	}
+	trailer := ""
+	if len(m.TrailerKeys) > 0 {
+		trailer = m.TrailerKeys[0]
+	}
+	m.Issues = append(m.Issues, IssueRef{Key: key, Trailer: trailer, Source: "typed", Selected: true})
+	m.issueCursor = len(m.Issues) - 1
+}
+
+// indexOf returns the position of s in list, or -1.
+func indexOf(list []string, s string) int {
+	for i, item := range list {
+		if item == s {
+			return i
+		}
+	}
+	return -1
+}
+
+// viewIssues renders the issue reference picker.
+func (m Model) viewIssues() string {
+	var b strings.Builder
+	b.WriteString(lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("#38BDF8")).Render("Issue references") + "\n")
+	if len(m.Issues) == 0 {
+		b.WriteString(lipgloss.NewStyle().Foreground(lipgloss.Color("244")).Render("  No issues found in the branch name or recent commits.") + "\n")
+	}
+	dim := lipgloss.NewStyle().Foreground(lipgloss.Color("244"))
+	for i, issue := range m.Issues {
+		cursor, check := "  ", "[ ]"
+		if issue.Selected {
+			check = "[x]"
+		}
+		line := fmt.Sprintf("%s %s: %s", check, issue.Trailer, issue.Key)
+		if i == m.issueCursor && !m.AddingIssue {
+			cursor = "> "
+			line = lipgloss.NewStyle().Bold(true).Underline(true).Render(line)
+		}
+		b.WriteString(cursor + line + " " + dim.Render("("+issue.Source+")") + "\n")
+	}
+	if m.AddingIssue {
+		b.WriteString("\n  " + m.IssueInput.View() + "\n")
+		b.WriteString(lipgloss.NewStyle().Foreground(lipgloss.Color("240")).Render("(Enter to add, Esc to cancel)"))
+	} else {
+		b.WriteString("\n" + lipgloss.NewStyle().Foreground(lipgloss.Color("240")).Render(
+			"space: toggle  •  t: trailer  •  a: add  •  enter: done"))
+	}
+	return b.String()
+}
+
+// maxCheckLines limits the check failures shown in the commit TUI.
+const maxCheckLines = 8
+
+// viewCheckFailures lists the problems found by the pre-commit checks:
+// failures first, then warnings.
+func (m Model) viewCheckFailures() string {
+	fail := lipgloss.NewStyle().Foreground(lipgloss.Color("#F472B6")).Bold(true)
+	warn := lipgloss.NewStyle().Foreground(lipgloss.Color("#F59E0B")).Bold(true)
+	dim := lipgloss.NewStyle().Foreground(lipgloss.Color("244"))
+
+	var lines []string
+	list := func(title string, problems []string) {
+		lines = append(lines, title)
+		for i, line := range problems {
+			if i == maxCheckLines {
+				lines = append(lines, dim.Render(fmt.Sprintf("  ... and %d more", len(problems)-maxCheckLines)))
+				break
+			}
+			lines = append(lines, dim.Render("  "+truncate(line, 70)))
+		}
+	}
+	if len(m.CheckFailures) > 0 {
+		list(fail.Render(fmt.Sprintf("✘ Pre-commit checks found %d problem(s):", len(m.CheckFailures))), m.CheckFailures)
+		hint := lipgloss.NewStyle().Foreground(lipgloss.Color("240"))
+		if m.checksBlocked {
+			hint = fail
+		}
+		lines = append(lines, hint.Render("Fix them and commit again, or commit with --no-verify to skip the checks."))
+	}
+	if len(m.CheckWarnings) > 0 {
+		list(warn.Render(fmt.Sprintf("⚠ %d warning(s):", len(m.CheckWarnings))), m.CheckWarnings)
+	}
+	return strings.Join(lines, "\n")
+}
+
 // View pushes the string representation of the UI.
 func (m Model) View() string {
 	if m.Quitting {
@@ -145,6 +373,9 @@ func (m Model) View() string {
 	} else {
 		msgContent = m.Message
 	}
+	if trailers := m.AllTrailers(); len(trailers) > 0 {
+		msgContent += "\n\n" + lipgloss.NewStyle().Foreground(lipgloss.Color("244")).Render(strings.Join(trailers, "\n"))
+	}
 
 	msgBox := lipgloss.NewStyle().
 		Border(lipgloss.RoundedBorder()).
@@ -154,6 +385,10 @@ func (m Model) View() string {
 		Width(60).
 		Render(msgContent)
 
+	if len(m.CheckFailures) > 0 || len(m.CheckWarnings) > 0 {
+		msgBox += "\n" + m.viewCheckFailures() + "\n"
+	}
+
 	// Button Styles
 	btnStyle := lipgloss.NewStyle().
 		Foreground(lipgloss.Color("#FFF7DB")).
@@ -171,7 +406,9 @@ func (m Model) View() string {
 	// Render choices
 	// Don't render buttons if editing
 	s := "\n"
-	if m.IsEditing {
+	if m.PickingIssues {
+		s += m.viewIssues()
+	} else if m.IsEditing {
 		s += lipgloss.NewStyle().Foreground(lipgloss.Color("240")).Render("(Enter to save, Esc to cancel editing)")
 	} else {
 		for i, choice := range m.choices {
@@ -181,7 +418,7 @@ func (m Model) View() string {
 				s += btnStyle.Render(choice)
 			}
 		}
-		s += "\n\n" + lipgloss.NewStyle().Foreground(lipgloss.Color("240")).Render("(Use arrows to navigate, Enter to select)")
+		s += "\n\n" + lipgloss.NewStyle().Foreground(lipgloss.Color("240")).Render("(Use arrows to navigate, Enter to select, i for issues)")
 	}
 
 	return fmt.Sprintf("\n%s\n%s\n%s", header, msgBox, s)
@@ -1,6 +1,7 @@
 package ui
 
 import (
+	"strings"
 	"testing"
 
 	tea "github.com/charmbracelet/bubbletea"
@@ -29,3 +30,79 @@ func TestModelUpdate(t *testing.T) {
 		t.Errorf("expected Quitting to be true")
 	}
 }
+
+func TestFullMessageAppendsTrailers(t *testing.T) {
+	m := InitialModel("fix(status): handle empty repo")
+	if m.FullMessage() != "fix(status): handle empty repo" {
+		t.Errorf("expected no trailers by default, got %q", m.FullMessage())
+	}
+
+	m.Trailers = []string{"Refs: JIRA-123"}
+	m.Message = "fix(status): edited"
+	if want := "fix(status): edited\n\nRefs: JIRA-123"; m.FullMessage() != want {
+		t.Errorf("FullMessage() = %q, want %q", m.FullMessage(), want)
+	}
+}
+
+func TestIssuePicker(t *testing.T) {
+	m := InitialModel("fix(ui): crash")
+	m.Issues = []IssueRef{
+		{Key: "ABC-42", Trailer: "Refs", Source: "branch", Selected: true},
+		{Key: "#7", Trailer: "Refs", Source: "recent commit"},
+	}
+
+	press := func(msgs ...tea.Msg) {
+		for _, msg := range msgs {
+			next, _ := m.Update(msg)
+			m = next.(Model)
+		}
+	}
+
+	press(runes("i"))
+	if !m.PickingIssues {
+		t.Fatal("expected i to open the issue picker")
+	}
+	// Switch ABC-42 to Closes, select #7, then type a bare number.
+	press(runes("t"), tea.KeyMsg{Type: tea.KeyDown}, runes(" "), runes("a"), runes("12"), tea.KeyMsg{Type: tea.KeyEnter})
+	press(tea.KeyMsg{Type: tea.KeyEnter})
+	if m.PickingIssues {
+		t.Fatal("expected enter to close the issue picker")
+	}
+
+	want := "fix(ui): crash\n\nCloses: ABC-42\nRefs: #7\nRefs: #12"
+	if got := m.FullMessage(); got != want {
+		t.Errorf("FullMessage() = %q, want %q", got, want)
+	}
+}
+
+func TestApplyBlockedByCheckFailures(t *testing.T) {
+	m := InitialModel("feat: add x")
+	m.CheckFailures = []string{"whitespace: main.go:3: trailing whitespace"}
+
+	next, _ := m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	m = next.(Model)
+	if m.Choice == ChoiceApply || m.Quitting {
+		t.Fatal("expected Apply to be refused while checks fail")
+	}
+	if !strings.Contains(m.View(), "main.go:3: trailing whitespace") {
+		t.Error("expected the failures in the view")
+	}
+
+	// Cancel still works.
+	next, _ = m.Update(runes("q"))
+	if m = next.(Model); m.Choice != ChoiceCancel {
+		t.Errorf("Choice = %v, want ChoiceCancel", m.Choice)
+	}
+}
+
+func TestCheckWarningsDoNotBlock(t *testing.T) {
+	m := InitialModel("feat: add x")
+	m.CheckWarnings = []string{"secrets: .env: file likely holds credentials"}
+	if !strings.Contains(m.View(), "1 warning(s)") {
+		t.Error("expected the warnings in the view")
+	}
+	next, _ := m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	if m = next.(Model); m.Choice != ChoiceApply {
+		t.Errorf("Choice = %v, want ChoiceApply despite warnings", m.Choice)
+	}
+}
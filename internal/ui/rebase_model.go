@@ -0,0 +1,201 @@
+package ui
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"raven/internal/git"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// RebaseActions are the actions of the rebase editor, in the order space cycles them.
+var RebaseActions = []string{"pick", "reword", "edit", "squash", "fixup", "drop"}
+
+// RebaseItem is a commit of the rebase and what to do with it.
+type RebaseItem struct {
+	Action string
+	Commit git.Message
+}
+
+// Subject returns the first line of the commit message.
+func (it RebaseItem) Subject() string {
+	subject, _, _ := strings.Cut(it.Commit.Text, "\n")
+	return subject
+}
+
+// RebaseModel edits the todo list of an interactive rebase: commits oldest
+// first, each with an action, reorderable.
+type RebaseModel struct {
+	Base      string
+	Items     []RebaseItem
+	Cursor    int
+	Confirmed bool // Start the rebase with Items
+	Err       error
+	Quitting  bool
+}
+
+// InitialRebaseModel lists commits (oldest first), all picked.
+func InitialRebaseModel(base string, commits []git.Message) RebaseModel {
+	items := make([]RebaseItem, len(commits))
+	for i, c := range commits {
+		items[i] = RebaseItem{Action: "pick", Commit: c}
+	}
+	return RebaseModel{Base: base, Items: items}
+}
+
+func (m RebaseModel) Init() tea.Cmd {
+	return nil
+}
+
+// Validate checks that the plan can be run.
+func (m RebaseModel) Validate() error {
+	for _, it := range m.Items {
+		switch it.Action {
+		case "drop":
+			continue
+		case "squash", "fixup":
+			return fmt.Errorf("cannot %s %q: there is no earlier commit to fold it into", it.Action, it.Subject())
+		}
+		return nil
+	}
+	return errors.New("every commit is dropped; use 'git reset' to discard them all")
+}
+
+func (m RebaseModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	key, ok := msg.(tea.KeyMsg)
+	if !ok {
+		return m, nil
+	}
+	m.Err = nil
+
+	switch key.String() {
+	case "q", "esc", "ctrl+c":
+		m.Quitting = true
+		return m, tea.Quit
+	case "enter":
+		if err := m.Validate(); err != nil {
+			m.Err = err
+			return m, nil
+		}
+		m.Confirmed = true
+		m.Quitting = true
+		return m, tea.Quit
+
+	case "up", "k":
+		if m.Cursor > 0 {
+			m.Cursor--
+		}
+	case "down", "j":
+		if m.Cursor < len(m.Items)-1 {
+			m.Cursor++
+		}
+	case "shift+up", "K": // Move the commit up
+		if m.Cursor > 0 {
+			m.Items[m.Cursor], m.Items[m.Cursor-1] = m.Items[m.Cursor-1], m.Items[m.Cursor]
+			m.Cursor--
+		}
+	case "shift+down", "J": // Move the commit down
+		if m.Cursor < len(m.Items)-1 {
+			m.Items[m.Cursor], m.Items[m.Cursor+1] = m.Items[m.Cursor+1], m.Items[m.Cursor]
+			m.Cursor++
+		}
+
+	case " ":
+		m.setAction(RebaseActions[(indexOf(RebaseActions, m.current())+1)%len(RebaseActions)])
+	case "p":
+		m.setAction("pick")
+	case "r":
+		m.setAction("reword")
+	case "e":
+		m.setAction("edit")
+	case "s":
+		m.setAction("squash")
+	case "f":
+		m.setAction("fixup")
+	case "d":
+		m.setAction("drop")
+	}
+	return m, nil
+}
+
+func (m RebaseModel) current() string {
+	if m.Cursor < len(m.Items) {
+		return m.Items[m.Cursor].Action
+	}
+	return ""
+}
+
+func (m *RebaseModel) setAction(action string) {
+	if m.Cursor < len(m.Items) {
+		m.Items[m.Cursor].Action = action
+	}
+}
+
+// Todo renders the plan as a git rebase todo list, to be applied to git's own
+// with git.EditTodo. messageFiles maps the
+// hashes of reworded commits to files holding their new messages; each is
+// picked and then amended with it.
+func (m RebaseModel) Todo(messageFiles map[string]string) string {
+	var b strings.Builder
+	for _, it := range m.Items {
+		action := it.Action
+		file, reword := messageFiles[it.Commit.Hash]
+		if action == "reword" {
+			action = "pick" // Without a new message, keep it as is
+		}
+		fmt.Fprintf(&b, "%s %s %s\n", action, it.Commit.Hash, it.Subject())
+		if it.Action == "reword" && reword {
+			fmt.Fprintf(&b, "exec git commit --amend --only --allow-empty --quiet --file %s\n", git.ShellQuote(file))
+		}
+	}
+	return b.String()
+}
+
+// actionColors tells the actions apart at a glance.
+var actionColors = map[string]string{
+	"pick":   "#38BDF8",
+	"reword": "#F59E0B",
+	"edit":   "#F59E0B",
+	"squash": "#34D399",
+	"fixup":  "#34D399",
+	"drop":   "#F472B6",
+}
+
+func (m RebaseModel) View() string {
+	if m.Quitting {
+		return ""
+	}
+
+	var s strings.Builder
+	s.WriteString(lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("#38BDF8")).Render("Rebase onto "+m.Base) + "\n")
+	s.WriteString(lipgloss.NewStyle().Foreground(lipgloss.Color("244")).Render("Oldest first. squash/fixup fold a commit into the one above it.") + "\n\n")
+
+	for i, it := range m.Items {
+		cursor := "  "
+		style := lipgloss.NewStyle()
+		if it.Action == "drop" {
+			style = style.Foreground(lipgloss.Color("240")).Strikethrough(true)
+		}
+		if i == m.Cursor {
+			cursor = "> "
+			style = style.Bold(true).Underline(true)
+		}
+		indent := ""
+		if it.Action == "squash" || it.Action == "fixup" {
+			indent = "└ "
+		}
+		action := lipgloss.NewStyle().Foreground(lipgloss.Color(actionColors[it.Action])).Render(fmt.Sprintf("%-7s", it.Action))
+		hash := lipgloss.NewStyle().Foreground(lipgloss.Color("240")).Render(it.Commit.ShortHash)
+		s.WriteString(cursor + action + " " + hash + " " + indent + style.Render(truncate(it.Subject(), 60)) + "\n")
+	}
+
+	if m.Err != nil {
+		s.WriteString("\n" + lipgloss.NewStyle().Foreground(lipgloss.Color("#F472B6")).Render("✘ "+m.Err.Error()) + "\n")
+	}
+	help := "↑/↓: navigate  •  K/J: move  •  p/r/e/s/f/d: pick, reword, edit, squash, fixup, drop  •  enter: start  •  q: abort"
+	s.WriteString(lipgloss.NewStyle().Foreground(lipgloss.Color("240")).MarginTop(1).Render(help))
+	return s.String()
+}
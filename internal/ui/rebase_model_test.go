@@ -0,0 +1,61 @@
+package ui
+
+import (
+	"strings"
+	"testing"
+
+	"raven/internal/git"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func rebaseModel() RebaseModel {
+	return InitialRebaseModel("main", []git.Message{
+		{Hash: "aaa", ShortHash: "a", Text: "feat: a"},
+		{Hash: "bbb", ShortHash: "b", Text: "feat: b"},
+		{Hash: "ccc", ShortHash: "c", Text: "fix: typo in a"},
+	})
+}
+
+func TestRebaseReorderAndTodo(t *testing.T) {
+	m := rebaseModel()
+	press := func(msgs ...tea.Msg) {
+		for _, msg := range msgs {
+			next, _ := m.Update(msg)
+			m = next.(RebaseModel)
+		}
+	}
+
+	// Move the typo fix under "feat: a" and fold it in, reword b.
+	press(runes("j"), runes("j"), runes("K"), runes("f"), runes("j"), runes("r"))
+	press(tea.KeyMsg{Type: tea.KeyEnter})
+	if !m.Confirmed {
+		t.Fatalf("expected the plan to be confirmed, got error %v", m.Err)
+	}
+
+	want := "pick aaa feat: a\n" +
+		"fixup ccc fix: typo in a\n" +
+		"pick bbb feat: b\n" +
+		"exec git commit --amend --only --allow-empty --quiet --file '/tmp/it'\\''s b'\n"
+	if got := m.Todo(map[string]string{"bbb": "/tmp/it's b"}); got != want {
+		t.Errorf("Todo() =\n%s\nwant\n%s", got, want)
+	}
+	if got := m.Todo(nil); !strings.Contains(got, "pick bbb") || strings.Contains(got, "exec") {
+		t.Errorf("expected a reword without message to stay a pick, got\n%s", got)
+	}
+}
+
+func TestRebaseRejectsLeadingSquash(t *testing.T) {
+	m := rebaseModel()
+	next, _ := m.Update(runes("d"))
+	m = next.(RebaseModel)
+	m.Cursor = 1
+	next, _ = m.Update(runes("s"))
+	m = next.(RebaseModel)
+
+	next, _ = m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	m = next.(RebaseModel)
+	if m.Confirmed || m.Err == nil {
+		t.Error("expected squashing into a dropped first commit to be refused")
+	}
+}
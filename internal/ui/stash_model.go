@@ -0,0 +1,359 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	"raven/internal/analysis"
+	"raven/internal/git"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	"github.com/charmbracelet/bubbles/viewport"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// StashMode is what the stash browser is currently asking for.
+type StashMode int
+
+const (
+	StashModeList        StashMode = iota
+	StashModeBranch                // Typing the name of a branch to create from the stash
+	StashModeConfirmDrop           // Waiting for y/n before dropping the stash
+)
+
+type stashesLoadedMsg struct {
+	stashes []git.Stash
+	err     error
+}
+
+type stashDetailMsg struct {
+	ref    string
+	detail string
+	err    error
+}
+
+// stashActionMsg reports the outcome of a git action; the list is reloaded after it.
+type stashActionMsg struct {
+	message string
+	err     error
+}
+
+// StashModel is the interactive stash list: stashes on top, the files and
+// diff of the highlighted one below.
+type StashModel struct {
+	Stashes  []git.Stash
+	Cursor   int
+	Mode     StashMode
+	Input    textinput.Model
+	Message  string // Outcome of the last action
+	Err      error
+	Quitting bool
+
+	detail      viewport.Model
+	detailRef   string
+	focusDetail bool
+	offset      int
+	width       int
+	height      int
+}
+
+// InitialStashModel creates the stash browser. Stashes are loaded in Init.
+func InitialStashModel() StashModel {
+	ti := textinput.New()
+	ti.Width = 60
+	ti.CharLimit = 200
+	return StashModel{
+		Input:  ti,
+		detail: viewport.New(80, 10),
+		width:  80,
+		height: 24,
+	}
+}
+
+func (m StashModel) load() tea.Cmd {
+	return func() tea.Msg {
+		stashes, err := git.GetStashes()
+		return stashesLoadedMsg{stashes: stashes, err: err}
+	}
+}
+
+func (m StashModel) Init() tea.Cmd {
+	return m.load()
+}
+
+// Selected returns the stash under the cursor, or nil if the list is empty.
+func (m StashModel) Selected() *git.Stash {
+	if m.Cursor < 0 || m.Cursor >= len(m.Stashes) {
+		return nil
+	}
+	return &m.Stashes[m.Cursor]
+}
+
+// BranchNameFor suggests a branch name for a stash: its message as a
+// Conventional header through the default template, else its slug.
+func BranchNameFor(s git.Stash) string {
+	if h, ok := analysis.ParseHeader(s.Message); ok {
+		return analysis.BranchName(analysis.DefaultBranchTemplate, h)
+	}
+	return analysis.Slugify(s.Message)
+}
+
+func (m StashModel) listHeight() int {
+	return max(min(len(m.Stashes), (m.height-8)/3), 3)
+}
+
+func (m StashModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.width = msg.Width
+		m.height = msg.Height
+		m.detail.Width = msg.Width
+		m.detail.Height = max(msg.Height-m.listHeight()-8, 3)
+		return m, nil
+
+	case stashesLoadedMsg:
+		m.Err = msg.err
+		m.Stashes = msg.stashes
+		m.Cursor = min(m.Cursor, max(len(m.Stashes)-1, 0))
+		m.scroll()
+		// Refs shift after a pop or drop, so always reload the detail.
+		m.detailRef = ""
+		m.detail.SetContent("")
+		return m, m.syncDetail()
+
+	case stashDetailMsg:
+		if msg.ref != m.detailRef {
+			return m, nil // Stale response
+		}
+		if msg.err != nil {
+			m.detail.SetContent("Error loading stash: " + msg.err.Error())
+		} else {
+			m.detail.SetContent(msg.detail)
+		}
+		m.detail.GotoTop()
+		return m, nil
+
+	case stashActionMsg:
+		m.Message = msg.message
+		m.Err = msg.err
+		return m, m.load()
+
+	case tea.KeyMsg:
+		if msg.String() == "ctrl+c" {
+			m.Quitting = true
+			return m, tea.Quit
+		}
+		switch m.Mode {
+		case StashModeConfirmDrop:
+			return m.updateConfirm(msg)
+		case StashModeBranch:
+			return m.updateInput(msg)
+		}
+		return m.updateList(msg)
+	}
+	return m, nil
+}
+
+func (m StashModel) updateList(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	s := m.Selected()
+	m.Message = ""
+	m.Err = nil
+
+	switch msg.String() {
+	case "q", "esc":
+		m.Quitting = true
+		return m, tea.Quit
+
+	case "tab":
+		m.focusDetail = !m.focusDetail
+		return m, nil
+	}
+
+	if m.focusDetail {
+		var cmd tea.Cmd
+		m.detail, cmd = m.detail.Update(msg)
+		return m, cmd
+	}
+
+	switch msg.String() {
+	case "up", "k":
+		if m.Cursor > 0 {
+			m.Cursor--
+		}
+	case "down", "j":
+		if m.Cursor < len(m.Stashes)-1 {
+			m.Cursor++
+		}
+	case "g", "home":
+		m.Cursor = 0
+	case "G", "end":
+		m.Cursor = max(len(m.Stashes)-1, 0)
+
+	case "enter", "a": // Apply
+		if s != nil {
+			ref := s.Ref
+			return m, stashAction(func() error { return git.StashApply(ref) }, "Applied "+ref)
+		}
+	case "p": // Pop
+		if s != nil {
+			ref := s.Ref
+			return m, stashAction(func() error { return git.StashPop(ref) }, "Popped "+ref)
+		}
+	case "d": // Drop after confirmation
+		if s != nil {
+			m.Mode = StashModeConfirmDrop
+		}
+	case "b": // Create a branch from the stash
+		if s != nil {
+			m.Mode = StashModeBranch
+			m.Input.Placeholder = "branch-name"
+			m.Input.SetValue(BranchNameFor(*s))
+			m.Input.CursorEnd()
+			m.Input.Focus()
+			return m, textinput.Blink
+		}
+	}
+	m.scroll()
+	return m, m.syncDetail()
+}
+
+func (m StashModel) updateInput(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.Type {
+	case tea.KeyEsc:
+		m.Mode = StashModeList
+		m.Input.Blur()
+		return m, nil
+
+	case tea.KeyEnter:
+		m.Mode = StashModeList
+		m.Input.Blur()
+		name := strings.TrimSpace(m.Input.Value())
+		s := m.Selected()
+		if s == nil || name == "" {
+			return m, nil
+		}
+		ref := s.Ref
+		return m, stashAction(func() error { return git.StashBranch(name, ref) }, "Created "+name+" from "+ref)
+	}
+
+	var cmd tea.Cmd
+	m.Input, cmd = m.Input.Update(msg)
+	return m, cmd
+}
+
+func (m StashModel) updateConfirm(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	m.Mode = StashModeList
+	s := m.Selected()
+	if s == nil || (msg.String() != "y" && msg.String() != "Y") {
+		m.Message = "Drop cancelled."
+		return m, nil
+	}
+	ref := s.Ref
+	return m, stashAction(func() error { return git.StashDrop(ref) }, "Dropped "+ref)
+}
+
+func stashAction(action func() error, success string) tea.Cmd {
+	return func() tea.Msg {
+		if err := action(); err != nil {
+			return stashActionMsg{err: err}
+		}
+		return stashActionMsg{message: success}
+	}
+}
+
+func (m *StashModel) scroll() {
+	h := m.listHeight()
+	if m.Cursor < m.offset {
+		m.offset = m.Cursor
+	} else if m.Cursor >= m.offset+h {
+		m.offset = m.Cursor - h + 1
+	}
+}
+
+// syncDetail requests the diff of the highlighted stash if it changed.
+func (m *StashModel) syncDetail() tea.Cmd {
+	s := m.Selected()
+	if s == nil || s.Ref == m.detailRef {
+		return nil
+	}
+	ref := s.Ref
+	m.detailRef = ref
+	m.detail.SetContent("Loading...")
+	return func() tea.Msg {
+		detail, err := git.ShowStash(ref)
+		return stashDetailMsg{ref: ref, detail: detail, err: err}
+	}
+}
+
+func (m StashModel) View() string {
+	if m.Quitting {
+		return ""
+	}
+
+	var s strings.Builder
+	s.WriteString(lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("#38BDF8")).Render("Stashes") + "\n\n")
+
+	if m.Err != nil && len(m.Stashes) == 0 {
+		s.WriteString(lipgloss.NewStyle().Foreground(lipgloss.Color("#F472B6")).Render("Error: "+m.Err.Error()) + "\n")
+	} else if len(m.Stashes) == 0 {
+		s.WriteString("No stashes. Run 'raven stash' to stash your changes.\n")
+	}
+
+	end := min(m.offset+m.listHeight(), len(m.Stashes))
+	for i := m.offset; i < end; i++ {
+		s.WriteString(m.renderStash(i) + "\n")
+	}
+
+	// Detail pane
+	if len(m.Stashes) > 0 {
+		sepColor := lipgloss.Color("240")
+		if m.focusDetail {
+			sepColor = lipgloss.Color("63")
+		}
+		s.WriteString(lipgloss.NewStyle().Foreground(sepColor).Render(strings.Repeat("─", m.width)) + "\n")
+		s.WriteString(m.detail.View() + "\n")
+	}
+
+	// Prompt or status line
+	s.WriteString("\n")
+	switch m.Mode {
+	case StashModeBranch:
+		s.WriteString("New branch: " + m.Input.View() + "\n")
+	case StashModeConfirmDrop:
+		s.WriteString(lipgloss.NewStyle().Foreground(lipgloss.Color("#F472B6")).Bold(true).
+			Render(fmt.Sprintf("Drop %s? [y/N]", m.Selected().Ref)) + "\n")
+	default:
+		if m.Err != nil && len(m.Stashes) > 0 {
+			s.WriteString(lipgloss.NewStyle().Foreground(lipgloss.Color("#F472B6")).Render("Error: "+m.Err.Error()) + "\n")
+		}
+		if m.Message != "" {
+			s.WriteString(lipgloss.NewStyle().Foreground(lipgloss.Color("#38BDF8")).Bold(true).Render("✔ "+m.Message) + "\n")
+		}
+	}
+
+	help := "↑/↓: navigate  •  tab: scroll diff  •  a: apply  •  p: pop  •  d: drop  •  b: branch  •  q: quit"
+	switch {
+	case m.Mode == StashModeBranch:
+		help = "enter: create branch  •  esc: cancel"
+	case m.focusDetail:
+		help = "↑/↓/pgup/pgdn: scroll diff  •  tab: back to list  •  q: quit"
+	}
+	s.WriteString(lipgloss.NewStyle().Foreground(lipgloss.Color("240")).Render(help))
+	return s.String()
+}
+
+func (m StashModel) renderStash(i int) string {
+	st := m.Stashes[i]
+
+	cursor := "  "
+	style := lipgloss.NewStyle()
+	if i == m.Cursor {
+		cursor = "> "
+		style = style.Bold(true).Underline(true)
+	}
+	dim := lipgloss.NewStyle().Foreground(lipgloss.Color("240"))
+	ref := lipgloss.NewStyle().Foreground(lipgloss.Color("#F59E0B")).Render(fmt.Sprintf("%-10s", st.Ref))
+	return cursor + ref + " " + style.Render(truncate(st.Message, 60)) + "  " +
+		dim.Render(fmt.Sprintf("on %s, %s", st.Branch, relativeAge(st.Date)))
+}
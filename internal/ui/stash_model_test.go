@@ -0,0 +1,51 @@
+package ui
+
+import (
+	"testing"
+
+	"raven/internal/git"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func loadedStashModel() StashModel {
+	m := InitialStashModel()
+	next, _ := m.Update(stashesLoadedMsg{stashes: []git.Stash{
+		{Ref: "stash@{0}", Branch: "main", Message: "feat(ui): add stash list"},
+		{Ref: "stash@{1}", Branch: "main", Message: "Half done refactor"},
+	}})
+	return next.(StashModel)
+}
+
+func TestStashDropNeedsConfirmation(t *testing.T) {
+	m := loadedStashModel()
+
+	next, _ := m.Update(runes("d"))
+	m = next.(StashModel)
+	if m.Mode != StashModeConfirmDrop {
+		t.Fatalf("expected drop to ask for confirmation")
+	}
+	next, cmd := m.Update(runes("n"))
+	m = next.(StashModel)
+	if m.Mode != StashModeList || cmd != nil {
+		t.Errorf("expected n to cancel without dropping")
+	}
+}
+
+func TestStashBranchPrefillsName(t *testing.T) {
+	m := loadedStashModel()
+
+	next, _ := m.Update(runes("b"))
+	m = next.(StashModel)
+	if m.Mode != StashModeBranch || m.Input.Value() != "feat/ui-add-stash-list" {
+		t.Errorf("expected branch prompt with the header as name, got %q", m.Input.Value())
+	}
+
+	next, _ = m.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	m = next.(StashModel)
+	next, _ = m.Update(tea.KeyMsg{Type: tea.KeyDown})
+	m = next.(StashModel)
+	if got := BranchNameFor(*m.Selected()); got != "half-done-refactor" {
+		t.Errorf("BranchNameFor() = %q, want %q", got, "half-done-refactor")
+	}
+}
@@ -0,0 +1,102 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	"raven/internal/stats"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+var sparkBlocks = []rune("▁▂▃▄▅▆▇█")
+
+// RenderSummary renders streaks, averages and distributions as a text panel.
+// It is used both by the stats TUI and by `raven stats --summary`.
+func RenderSummary(s stats.Summary) string {
+	title := lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("#38BDF8"))
+	label := lipgloss.NewStyle().Foreground(lipgloss.Color("244")).Width(22)
+	value := lipgloss.NewStyle().Foreground(lipgloss.Color("#FFF7DB")).Bold(true)
+	dim := lipgloss.NewStyle().Foreground(lipgloss.Color("240"))
+
+	var b strings.Builder
+	b.WriteString(title.Render(fmt.Sprintf("Summary %s – %s", s.Start, s.End)) + "\n\n")
+
+	line := func(name, val, note string) {
+		b.WriteString(label.Render(name) + value.Render(val))
+		if note != "" {
+			b.WriteString(" " + dim.Render(note))
+		}
+		b.WriteString("\n")
+	}
+
+	line("Commits", fmt.Sprintf("%d", s.TotalCommits), fmt.Sprintf("on %d active days", s.ActiveDays))
+	line("Current streak", pluralDays(s.CurrentStreak), "")
+	longestNote := ""
+	if s.LongestStreak > 0 {
+		longestNote = fmt.Sprintf("(%s → %s)", s.LongestStreakStart, s.LongestStreakEnd)
+	}
+	line("Longest streak", pluralDays(s.LongestStreak), longestNote)
+	if s.BusiestDay != "" {
+		line("Busiest day", s.BusiestDay, fmt.Sprintf("%d commits", s.BusiestDayCommits))
+	}
+	line("Avg per active day", fmt.Sprintf("%.1f", s.AveragePerActiveDay), "")
+
+	// Weekday distribution as horizontal bars
+	b.WriteString("\n" + title.Render("By weekday") + "\n")
+	maxDay := 0
+	for _, n := range s.Weekdays {
+		maxDay = max(maxDay, n)
+	}
+	bar := lipgloss.NewStyle().Foreground(lipgloss.Color("#0EA5E9"))
+	names := []string{"Sun", "Mon", "Tue", "Wed", "Thu", "Fri", "Sat"}
+	for i, n := range s.Weekdays {
+		width := 0
+		if maxDay > 0 {
+			width = n * 30 / maxDay
+		}
+		if n > 0 && width == 0 {
+			width = 1
+		}
+		b.WriteString(fmt.Sprintf("%s %s %s\n", dim.Render(names[i]), bar.Render(strings.Repeat("█", width)), dim.Render(fmt.Sprintf("%d", n))))
+	}
+
+	// Hour-of-day histogram as a sparkline
+	b.WriteString("\n" + title.Render("By hour of day") + "\n")
+	maxHour := 0
+	for _, n := range s.Hours {
+		maxHour = max(maxHour, n)
+	}
+	var spark strings.Builder
+	for _, n := range s.Hours {
+		if n == 0 || maxHour == 0 {
+			spark.WriteString(" ")
+			continue
+		}
+		spark.WriteRune(sparkBlocks[(n*(len(sparkBlocks)-1))/maxHour])
+	}
+	b.WriteString(bar.Render(spark.String()) + "\n")
+	b.WriteString(dim.Render("0     6     12    18  23"))
+
+	return b.String()
+}
+
+func pluralDays(n int) string {
+	if n == 1 {
+		return "1 day"
+	}
+	return fmt.Sprintf("%d days", n)
+}
+
+// viewSummary renders the summary panel for the visible range of the heatmap.
+func (m CalendarModel) viewSummary() string {
+	if !m.ShowSummary {
+		return ""
+	}
+	start, end := m.visibleRange()
+	return "\n" + lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(lipgloss.Color("240")).
+		Padding(0, 2).
+		Render(RenderSummary(m.Activity.Summarize(start, end)))
+}
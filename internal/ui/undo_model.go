@@ -0,0 +1,105 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	"raven/internal/undo"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// UndoModel picks a step of the branch's reflog to go back before. Every
+// step from the newest down to the chosen one is undone.
+type UndoModel struct {
+	Branch   string
+	Steps    []undo.Step
+	Cursor   int
+	Chosen   int // Index of the chosen step, -1 if none
+	Message  string
+	Quitting bool
+}
+
+// InitialUndoModel creates the picker over steps (newest first).
+func InitialUndoModel(branch string, steps []undo.Step) UndoModel {
+	return UndoModel{Branch: branch, Steps: steps, Chosen: -1}
+}
+
+func (m UndoModel) Init() tea.Cmd {
+	return nil
+}
+
+func (m UndoModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	key, ok := msg.(tea.KeyMsg)
+	if !ok {
+		return m, nil
+	}
+	m.Message = ""
+	switch key.String() {
+	case "q", "esc", "ctrl+c":
+		m.Quitting = true
+		return m, tea.Quit
+	case "up", "k":
+		if m.Cursor > 0 {
+			m.Cursor--
+		}
+	case "down", "j":
+		if m.Cursor < len(m.Steps)-1 {
+			m.Cursor++
+		}
+	case "enter":
+		if m.Cursor < len(m.Steps) {
+			if !m.Steps[m.Cursor].Undoable {
+				m.Message = "What came before this step is unknown."
+				return m, nil
+			}
+			m.Chosen = m.Cursor
+			m.Quitting = true
+			return m, tea.Quit
+		}
+	}
+	return m, nil
+}
+
+func (m UndoModel) View() string {
+	if m.Quitting {
+		return ""
+	}
+
+	var s strings.Builder
+	s.WriteString(lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("#38BDF8")).Render("Undo history of "+m.Branch) + "\n\n")
+	if len(m.Steps) == 0 {
+		s.WriteString("Nothing to undo.\n")
+	}
+
+	dim := lipgloss.NewStyle().Foreground(lipgloss.Color("240"))
+	for i, step := range m.Steps {
+		cursor, marker := "  ", "     "
+		style := lipgloss.NewStyle()
+		if step.Entry != nil {
+			style = style.Foreground(lipgloss.Color("#38BDF8")) // Recorded by raven
+		}
+		if !step.Undoable {
+			style = dim
+		}
+		if i <= m.Cursor {
+			marker = lipgloss.NewStyle().Foreground(lipgloss.Color("#F472B6")).Render("undo ")
+		}
+		if i == m.Cursor {
+			cursor = "> "
+			style = style.Bold(true).Underline(true)
+		}
+		ref := lipgloss.NewStyle().Foreground(lipgloss.Color("#F59E0B")).Render(undo.Short(step.After))
+		s.WriteString(cursor + marker + ref + " " + style.Render(truncate(step.Message, 60)) + "  " + dim.Render(relativeAge(step.Date)) + "\n")
+	}
+
+	if m.Cursor < len(m.Steps) && m.Steps[m.Cursor].Undoable {
+		s.WriteString("\n" + fmt.Sprintf("→ %s goes back to %s. The working tree is not touched.", m.Branch, undo.Short(m.Steps[m.Cursor].Before)) + "\n")
+	}
+	if m.Message != "" {
+		s.WriteString(lipgloss.NewStyle().Foreground(lipgloss.Color("#F472B6")).Render(m.Message) + "\n")
+	}
+	s.WriteString(lipgloss.NewStyle().Foreground(lipgloss.Color("240")).MarginTop(1).Render("↑/↓: navigate  •  enter: undo up to here  •  q: quit"))
+	return s.String()
+}
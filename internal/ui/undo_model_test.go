@@ -0,0 +1,32 @@
+package ui
+
+import (
+	"testing"
+
+	"raven/internal/undo"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestUndoPickerSkipsUnknownSteps(t *testing.T) {
+	m := InitialUndoModel("main", []undo.Step{
+		{N: 0, Before: "b", After: "c", Message: "raven fix: feat: x", Undoable: true},
+		{N: 1, After: "b", Message: "branch: Created from HEAD"},
+	})
+
+	next, _ := m.Update(tea.KeyMsg{Type: tea.KeyDown})
+	m = next.(UndoModel)
+	next, cmd := m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	m = next.(UndoModel)
+	if m.Chosen != -1 || cmd != nil || m.Message == "" {
+		t.Fatalf("expected the creation step to be refused, chosen %d", m.Chosen)
+	}
+
+	next, _ = m.Update(tea.KeyMsg{Type: tea.KeyUp})
+	m = next.(UndoModel)
+	next, _ = m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	m = next.(UndoModel)
+	if m.Chosen != 0 || !m.Quitting {
+		t.Errorf("expected the first step to be chosen, got %d", m.Chosen)
+	}
+}
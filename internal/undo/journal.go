@@ -0,0 +1,182 @@
+// Package undo records the operations raven performs on the current branch
+// (the branch and index before and after each one) so that they can be
+// undone and redone safely.
+package undo
+
+import (
+	"bufio"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"raven/internal/git"
+)
+
+// Operation names of the entries written by Restore.
+const (
+	OpUndo = "undo"
+	OpRedo = "redo"
+)
+
+// Entry is a recorded operation.
+type Entry struct {
+	ID          int       `json:"id"`
+	Op          string    `json:"op"`                    // "commit", "save", "fix", "amend", "undo", "redo"
+	Ref         string    `json:"ref"`                   // Branch that moved, or "HEAD" when detached
+	Before      string    `json:"before,omitempty"`      // Commit before, "" if the branch was unborn
+	After       string    `json:"after,omitempty"`       // Commit after, "" if the branch became unborn
+	IndexBefore string    `json:"indexBefore,omitempty"` // Tree of the index before, "" if unknown
+	IndexAfter  string    `json:"indexAfter,omitempty"`
+	Target      int       `json:"target,omitempty"` // Undo/redo: ID of the entry undone/redone, 0 if none
+	Subject     string    `json:"subject,omitempty"`
+	Time        time.Time `json:"time"`
+}
+
+// Journal is the list of recorded entries, oldest first.
+type Journal []Entry
+
+// journalFile is the journal's path inside the git directory. The index trees
+// it refers to are not referenced elsewhere, so git gc may prune them after
+// its usual grace period; older entries then restore the branch only.
+const journalFile = "raven/journal"
+
+// maxEntries is how many entries the journal keeps.
+const maxEntries = 200
+
+// Load reads the journal of the current repository. A missing journal is empty.
+func Load() (Journal, error) {
+	path, err := git.GitPath(journalFile)
+	if err != nil {
+		return nil, err
+	}
+	f, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var journal Journal
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		var e Entry
+		if json.Unmarshal(scanner.Bytes(), &e) == nil {
+			journal = append(journal, e)
+		}
+	}
+	return journal, scanner.Err()
+}
+
+// record appends an entry to the journal, assigning its ID.
+func record(e Entry) (Entry, error) {
+	journal, err := Load()
+	if err != nil {
+		return e, err
+	}
+	e.ID = 1
+	if len(journal) > 0 {
+		e.ID = journal[len(journal)-1].ID + 1
+	}
+	e.Time = time.Now()
+	journal = append(journal, e)
+	if len(journal) > maxEntries {
+		journal = journal[len(journal)-maxEntries:]
+	}
+
+	path, err := git.GitPath(journalFile)
+	if err != nil {
+		return e, err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return e, err
+	}
+	var b strings.Builder
+	for _, entry := range journal {
+		line, _ := json.Marshal(entry)
+		b.Write(line)
+		b.WriteByte('\n')
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, []byte(b.String()), 0o644); err != nil {
+		return e, err
+	}
+	return e, os.Rename(tmp, path)
+}
+
+// Find returns the entry with the given ID.
+func (j Journal) Find(id int) (Entry, bool) {
+	for _, e := range j {
+		if e.ID == id {
+			return e, true
+		}
+	}
+	return Entry{}, false
+}
+
+// undone reports, by ID, which entries are currently undone, and which undo
+// entries have been redone.
+func (j Journal) undone() (undone, redone map[int]bool) {
+	undone = make(map[int]bool)
+	redone = make(map[int]bool)
+	for _, e := range j {
+		switch e.Op {
+		case OpUndo:
+			undone[e.Target] = true
+		case OpRedo:
+			redone[e.Target] = true
+			if u, ok := j.Find(e.Target); ok {
+				undone[u.Target] = false
+			}
+		}
+	}
+	return undone, redone
+}
+
+// NextUndo returns the latest operation on ref that has not been undone.
+func (j Journal) NextUndo(ref string) (Entry, bool) {
+	undone, _ := j.undone()
+	for i := len(j) - 1; i >= 0; i-- {
+		e := j[i]
+		if e.Ref == ref && e.Op != OpUndo && e.Op != OpRedo && !undone[e.ID] {
+			return e, true
+		}
+	}
+	return Entry{}, false
+}
+
+// NextRedo returns the latest undo on ref that has not been redone. A new
+// operation after an undo clears what can be redone.
+func (j Journal) NextRedo(ref string) (Entry, bool) {
+	_, redone := j.undone()
+	for i := len(j) - 1; i >= 0; i-- {
+		e := j[i]
+		if e.Ref != ref {
+			continue
+		}
+		switch e.Op {
+		case OpUndo:
+			if !redone[e.ID] {
+				return e, true
+			}
+		case OpRedo:
+			continue
+		default:
+			return Entry{}, false
+		}
+	}
+	return Entry{}, false
+}
+
+// Match returns the latest entry that moved ref from before to after.
+func (j Journal) Match(ref, before, after string) (Entry, bool) {
+	for i := len(j) - 1; i >= 0; i-- {
+		if e := j[i]; e.Ref == ref && e.Before == before && e.After == after {
+			return e, true
+		}
+	}
+	return Entry{}, false
+}
@@ -0,0 +1,45 @@
+package undo
+
+import "testing"
+
+func TestNextUndoAndRedo(t *testing.T) {
+	j := Journal{
+		{ID: 1, Op: "commit", Ref: "refs/heads/main", After: "a"},
+		{ID: 2, Op: "fix", Ref: "refs/heads/main", Before: "a", After: "b"},
+	}
+	if e, ok := j.NextUndo("refs/heads/main"); !ok || e.ID != 2 {
+		t.Fatalf("NextUndo() = %v, %v; want entry 2", e.ID, ok)
+	}
+	if _, ok := j.NextRedo("refs/heads/main"); ok {
+		t.Fatal("expected nothing to redo before an undo")
+	}
+
+	// Undo twice: entries 2 then 1.
+	j = append(j, Entry{ID: 3, Op: OpUndo, Ref: "refs/heads/main", Target: 2}, Entry{ID: 4, Op: OpUndo, Ref: "refs/heads/main", Target: 1})
+	if _, ok := j.NextUndo("refs/heads/main"); ok {
+		t.Fatal("expected nothing left to undo")
+	}
+	if u, ok := j.NextRedo("refs/heads/main"); !ok || u.ID != 4 {
+		t.Fatalf("NextRedo() = %v, %v; want undo entry 4", u.ID, ok)
+	}
+
+	// Redo the last undo: entry 1 is back, entry 3 is next to redo.
+	j = append(j, Entry{ID: 5, Op: OpRedo, Ref: "refs/heads/main", Target: 4})
+	if e, ok := j.NextUndo("refs/heads/main"); !ok || e.ID != 1 {
+		t.Errorf("NextUndo() = %v, %v; want entry 1", e.ID, ok)
+	}
+	if u, ok := j.NextRedo("refs/heads/main"); !ok || u.ID != 3 {
+		t.Errorf("NextRedo() = %v, %v; want undo entry 3", u.ID, ok)
+	}
+
+	// Operations on other branches are ignored.
+	if _, ok := j.NextUndo("refs/heads/other"); ok {
+		t.Error("expected nothing to undo on another branch")
+	}
+
+	// A new operation clears the redo stack.
+	j = append(j, Entry{ID: 6, Op: "commit", Ref: "refs/heads/main", Before: "a", After: "c"})
+	if _, ok := j.NextRedo("refs/heads/main"); ok {
+		t.Error("expected a new operation to clear redo")
+	}
+}
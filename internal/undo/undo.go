@@ -0,0 +1,181 @@
+package undo
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+
+	"raven/internal/git"
+)
+
+// Operation is an operation in progress: Begin captures the state before,
+// Finish records it once git is done.
+type Operation struct {
+	Name        string
+	ref         string
+	before      string
+	indexBefore string
+}
+
+// Begin captures the current branch and index before an operation.
+func Begin(name string) *Operation {
+	ref := git.HeadRef()
+	index, _ := git.WriteTree()
+	return &Operation{Name: name, ref: ref, before: git.RevParse(ref), indexBefore: index}
+}
+
+// Git returns a git command whose reflog entries are tagged "raven <name>".
+func (o *Operation) Git(args ...string) *exec.Cmd {
+	cmd := exec.Command("git", args...)
+	cmd.Env = append(os.Environ(), "GIT_REFLOG_ACTION=raven "+o.Name)
+	return cmd
+}
+
+// Finish records the operation if it moved the branch.
+func (o *Operation) Finish() error {
+	after := git.RevParse(o.ref)
+	if after == o.before {
+		return nil
+	}
+	index, _ := git.WriteTree()
+	_, err := record(Entry{
+		Op:          o.Name,
+		Ref:         o.ref,
+		Before:      o.before,
+		After:       after,
+		IndexBefore: o.indexBefore,
+		IndexAfter:  index,
+		Subject:     git.Subject(after),
+	})
+	return err
+}
+
+// State is a branch position and index, the "" values meaning an unborn
+// branch and an unknown index.
+type State struct {
+	Commit string
+	Index  string
+}
+
+// Restore moves ref from one state back to another, recording it as op
+// (OpUndo or OpRedo) of the entry target (0 if none). The working tree is
+// never touched. The index is restored only if it is unchanged since from,
+// so staging done in the meantime is kept; Restore reports whether it was.
+func Restore(op, ref string, from, to State, target int) (bool, error) {
+	current := git.RevParse(ref)
+	if current != from.Commit {
+		return false, fmt.Errorf("%s has moved since (now at %s); refusing to overwrite it", shortRef(ref), Short(current))
+	}
+
+	indexNow, _ := git.WriteTree()
+	var err error
+	if to.Commit == "" {
+		err = git.DeleteRef(ref, from.Commit)
+	} else {
+		err = git.UpdateRef(ref, to.Commit, from.Commit, "raven "+op+": moving to "+Short(to.Commit))
+	}
+	if err != nil {
+		return false, err
+	}
+
+	restored := to.Index != "" && from.Index != "" && indexNow == from.Index && git.ReadTree(to.Index) == nil
+	indexAfter := indexNow
+	if restored {
+		indexAfter = to.Index
+	}
+	_, err = record(Entry{
+		Op:          op,
+		Ref:         ref,
+		Before:      from.Commit,
+		After:       to.Commit,
+		IndexBefore: indexNow,
+		IndexAfter:  indexAfter,
+		Target:      target,
+		Subject:     git.Subject(to.Commit),
+	})
+	return restored, err
+}
+
+// Undo reverts a recorded operation.
+func Undo(e Entry) (bool, error) {
+	return Restore(OpUndo, e.Ref, State{e.After, e.IndexAfter}, State{e.Before, e.IndexBefore}, e.ID)
+}
+
+// Redo reverts an undo entry.
+func Redo(u Entry) (bool, error) {
+	return Restore(OpRedo, u.Ref, State{u.After, u.IndexAfter}, State{u.Before, u.IndexBefore}, u.ID)
+}
+
+// Step is a move of a branch, read from its reflog.
+type Step struct {
+	N        int    // Position in the reflog, 0 = latest
+	Before   string // "" if the move created the branch's first commit
+	After    string
+	Message  string // e.g. "raven fix: feat: add x" or "reset: moving to HEAD~1"
+	Date     time.Time
+	Entry    *Entry // Matching journal entry, nil if raven did not record it
+	Undoable bool   // False when what came before is unknown (expired reflog, new branch)
+}
+
+// Steps returns up to max moves of ref, newest first.
+func Steps(ref string, max int, journal Journal) ([]Step, error) {
+	entries, err := git.Reflog(ref, max+1)
+	if err != nil {
+		return nil, err
+	}
+	var steps []Step
+	for i, e := range entries {
+		if i == max {
+			break
+		}
+		s := Step{N: i, After: e.Hash, Message: e.Message, Date: e.Date, Undoable: true}
+		if i+1 < len(entries) {
+			s.Before = entries[i+1].Hash
+		} else {
+			// Oldest entry: only a root commit can be undone, to an unborn branch.
+			s.Undoable = !git.HasParent(e.Hash) && !strings.HasPrefix(e.Message, "branch:")
+		}
+		if m, ok := journal.Match(ref, s.Before, s.After); ok {
+			s.Entry = &m
+		}
+		steps = append(steps, s)
+	}
+	return steps, nil
+}
+
+// UndoSteps restores ref to the state before steps[n], undoing steps 0..n.
+// The index is restored when raven recorded both ends.
+func UndoSteps(ref string, steps []Step, n int) (bool, error) {
+	target := steps[n]
+	if !target.Undoable {
+		return false, fmt.Errorf("the state before %q is unknown", target.Message)
+	}
+	from := State{Commit: steps[0].After}
+	if steps[0].Entry != nil {
+		from.Index = steps[0].Entry.IndexAfter
+	}
+	to := State{Commit: target.Before}
+	id := 0
+	if target.Entry != nil {
+		to.Index = target.Entry.IndexBefore
+		id = target.Entry.ID
+	}
+	return Restore(OpUndo, ref, from, to, id)
+}
+
+// Short abbreviates a commit hash; "" is shown as the unborn state.
+func Short(hash string) string {
+	if hash == "" {
+		return "(no commits)"
+	}
+	if len(hash) > 7 {
+		return hash[:7]
+	}
+	return hash
+}
+
+func shortRef(ref string) string {
+	return strings.TrimPrefix(ref, "refs/heads/")
+}
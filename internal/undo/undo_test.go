@@ -0,0 +1,146 @@
+package undo
+
+import (
+	"os"
+	"os/exec"
+	"strings"
+	"testing"
+
+	"raven/internal/git"
+)
+
+// gitRepo creates a repository and makes it the working directory.
+func gitRepo(t *testing.T) func(args ...string) string {
+	t.Helper()
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not installed")
+	}
+	t.Chdir(t.TempDir())
+	t.Setenv("GIT_CONFIG_GLOBAL", "/dev/null")
+	t.Setenv("GIT_AUTHOR_NAME", "Alice")
+	t.Setenv("GIT_AUTHOR_EMAIL", "alice@example.com")
+	t.Setenv("GIT_COMMITTER_NAME", "Alice")
+	t.Setenv("GIT_COMMITTER_EMAIL", "alice@example.com")
+	run := func(args ...string) string {
+		t.Helper()
+		out, err := exec.Command("git", args...).CombinedOutput()
+		if err != nil {
+			t.Fatalf("git %v: %v\n%s", args, err, out)
+		}
+		return strings.TrimSpace(string(out))
+	}
+	run("init", "-q", "-b", "main")
+	return run
+}
+
+// commit records a raven operation committing name.
+func commit(t *testing.T, op, name, msg string, amend bool) {
+	t.Helper()
+	if err := os.WriteFile(name, []byte(msg+"\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	o := Begin(op)
+	args := []string{"commit", "-q", "-m", msg}
+	if amend {
+		args = append(args, "--amend")
+	}
+	if out, err := exec.Command("git", "add", name).CombinedOutput(); err != nil {
+		t.Fatalf("git add: %v\n%s", err, out)
+	}
+	if out, err := o.Git(args...).CombinedOutput(); err != nil {
+		t.Fatalf("git commit: %v\n%s", err, out)
+	}
+	if err := o.Finish(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestUndoRootCommitAndRedo(t *testing.T) {
+	run := gitRepo(t)
+	commit(t, "save", "a.txt", "feat: first", false)
+	root := git.RevParse("HEAD")
+
+	journal, _ := Load()
+	e, ok := journal.NextUndo("refs/heads/main")
+	if !ok || e.Before != "" || e.After != root {
+		t.Fatalf("expected the root commit to be recorded, got %+v", e)
+	}
+	if _, err := Undo(e); err != nil {
+		t.Fatal(err)
+	}
+	if git.RevParse("HEAD") != "" {
+		t.Fatal("expected undoing the root commit to leave the branch unborn")
+	}
+	// The save began before staging, so a.txt is back to untracked.
+	if got := run("status", "--porcelain"); got != "?? a.txt" {
+		t.Errorf("expected a.txt back to untracked, got %q", got)
+	}
+
+	journal, _ = Load()
+	u, ok := journal.NextRedo("refs/heads/main")
+	if !ok {
+		t.Fatal("expected the undo to be redoable")
+	}
+	if _, err := Redo(u); err != nil {
+		t.Fatal(err)
+	}
+	if git.RevParse("HEAD") != root {
+		t.Error("expected redo to restore the root commit")
+	}
+}
+
+func TestUndoAmendRestoresIndex(t *testing.T) {
+	run := gitRepo(t)
+	commit(t, "commit", "a.txt", "feat: first", false)
+	first := git.RevParse("HEAD")
+	commit(t, "fix", "b.txt", "feat: first", true)
+
+	journal, _ := Load()
+	e, _ := journal.NextUndo("refs/heads/main")
+	if e.Op != "fix" {
+		t.Fatalf("expected the fix to be undone first, got %q", e.Op)
+	}
+	restored, err := Undo(e)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !restored || git.RevParse("HEAD") != first {
+		t.Fatalf("expected HEAD back at the amended commit with its index, restored=%v", restored)
+	}
+	if got := run("status", "--porcelain"); got != "?? b.txt" {
+		t.Errorf("expected b.txt back to untracked, got %q", got)
+	}
+
+	// HEAD moved outside raven: the recorded state no longer applies.
+	run("commit", "-q", "--allow-empty", "-m", "chore: manual")
+	journal, _ = Load()
+	u, _ := journal.NextRedo("refs/heads/main")
+	if _, err := Redo(u); err == nil {
+		t.Error("expected redo to refuse after HEAD moved")
+	}
+}
+
+func TestStepsFromReflog(t *testing.T) {
+	gitRepo(t)
+	commit(t, "commit", "a.txt", "feat: first", false)
+	commit(t, "commit", "b.txt", "feat: second", false)
+
+	journal, _ := Load()
+	steps, err := Steps("refs/heads/main", 10, journal)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(steps) != 2 || !strings.HasPrefix(steps[0].Message, "raven commit: feat: second") {
+		t.Fatalf("unexpected steps %+v", steps)
+	}
+	if steps[0].Entry == nil || !steps[1].Undoable || steps[1].Before != "" {
+		t.Errorf("expected both steps to be matched and undoable, got %+v", steps)
+	}
+
+	if _, err := UndoSteps("refs/heads/main", steps, 1); err != nil {
+		t.Fatal(err)
+	}
+	if git.RevParse("HEAD") != "" {
+		t.Error("expected undoing both steps to leave the branch unborn")
+	}
+}